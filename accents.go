@@ -0,0 +1,188 @@
+package gorldline
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// Accented spellings of words commonly found in menus. Ambiguous words ("des", "ou", "mais") are left out on purpose.
+	frenchAccentedWords = []string{
+		// Vegetables, fruits and sides.
+		"légume", "légumineuse", "céleri", "épinard", "échalote", "cèpe", "châtaigne", "clémentine",
+		"pêche", "frisée", "maïs", "blé", "épeautre", "céréale", "purée", "pâtes", "croûton", "croûte", "fève",
+		"mélange", "méli", "mélo", "variété", "crudité", "poêlée", "fricassée", "panachée",
+		// Meat and fish.
+		"bœuf", "œuf", "côte", "côtelette", "entrecôte", "échine", "épaule", "suprême", "médaillon", "ragoût",
+		"gésier", "pâté", "pavé", "écrevisse", "églefin", "flétan", "crustacé", "calamar", "marée", "océane",
+		// Dairy and desserts.
+		"crème", "crêpe", "gâteau", "pâtisserie", "pâtissier", "pâtissière", "génoise", "éclair", "île", "brûlée",
+		"chèvre", "gruyère", "café", "liégeois", "forêt", "sucré", "salé", "glacé", "gelée", "fraîche", "fraîcheur",
+		"cœur",
+		// Cooking methods.
+		"rôti", "sauté", "poêlé", "pané", "grillé", "braisé", "mijoté", "gratiné", "émincé", "haché",
+		"poché", "fumé", "séché", "doré", "rissolé", "râpé", "émietté", "tranché", "étuvé", "mariné", "caramélisé",
+		"épicé", "pimenté", "assaisonné", "persillé", "roulé", "velouté", "soufflé", "mêlé",
+		// Sauces, styles and origins.
+		"béchamel", "béarnaise", "meunière", "forestière", "provençal", "provençale", "niçoise", "créole",
+		"méditerranéen", "méditerranéenne", "thaï", "végétarien", "végétarienne", "végétal", "végétale", "végan",
+		"épice", "spécialité", "entrée",
+	}
+
+	// Names that collide with menu words, never restored even in title-cased labels.
+	frenchProperNouns = []string{
+		"Dore", "Mele", "Sale",
+	}
+
+	// Words that are already correct without accents although an accented word folds to them.
+	frenchUnaccentedWords = []string{
+		"glace", "glaces", "mais",
+	}
+
+	DefaultAccentRestorer = NewAccentRestorer(frenchAccentedWords, frenchProperNouns, frenchUnaccentedWords)
+)
+
+func NewAccentRestorer(words, properNouns, unaccented []string) *AccentRestorer {
+	r := new(AccentRestorer)
+	r.words = make(map[string]map[string]struct{}, len(words))
+	r.inflections = make(map[string]map[string]struct{}, len(words))
+	r.properNouns = make(map[string]struct{}, len(properNouns))
+	r.unaccented = make(map[string]struct{}, len(unaccented))
+
+	for _, w := range words {
+		r.AddWord(w)
+	}
+	for _, p := range properNouns {
+		r.properNouns[p] = struct{}{}
+	}
+	for _, u := range unaccented {
+		r.unaccented[strings.ToLower(u)] = struct{}{}
+	}
+
+	return r
+}
+
+type AccentRestorer struct {
+	words       map[string]map[string]struct{}
+	inflections map[string]map[string]struct{}
+	properNouns map[string]struct{}
+	unaccented  map[string]struct{}
+}
+
+// Inflected forms are registered as whole words, so that "epicee" becomes "épicée" and never "épicee".
+func (r *AccentRestorer) AddWord(word string) {
+	word = strings.ToLower(word)
+	addAccentedForm(r.words, word)
+	for _, form := range inflectedForms(word) {
+		addAccentedForm(r.inflections, form)
+	}
+}
+
+func addAccentedForm(forms map[string]map[string]struct{}, form string) {
+	key := foldAccents(form)
+	if forms[key] == nil {
+		forms[key] = make(map[string]struct{})
+	}
+	forms[key][form] = struct{}{}
+}
+
+func (r *AccentRestorer) Name() string {
+	return RuleDictionary
+}
+
+func (r *AccentRestorer) Apply(s string) string {
+	words := wordRegex.FindAllStringIndex(s, -1)
+	titleCase := isTitleCase(s, words)
+
+	var b strings.Builder
+	last := 0
+	for i, w := range words {
+		word := s[w[0]:w[1]]
+
+		// Capitalised words in the middle of a regular sentence are most likely proper nouns.
+		first, _ := utf8.DecodeRuneInString(word)
+		if i > 0 && unicode.IsUpper(first) && !titleCase {
+			continue
+		}
+		if _, pres := r.properNouns[word]; pres {
+			continue
+		}
+
+		restored, ok := r.restore(word)
+		if !ok {
+			continue
+		}
+
+		b.WriteString(s[last:w[0]])
+		b.WriteString(matchWordCase(word, restored))
+		last = w[1]
+	}
+
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// Words folding to several accented forms (épice, épicé) are ambiguous and left untouched.
+func (r *AccentRestorer) restore(word string) (string, bool) {
+	lower := strings.ToLower(word)
+	if foldAccents(lower) != lower {
+		return "", false
+	}
+	if _, pres := r.unaccented[lower]; pres {
+		return "", false
+	}
+
+	// A listed word wins over the inflection of another one: "pates" is "pâtes", not "pâtés".
+	forms, pres := r.words[lower]
+	if !pres {
+		forms = r.inflections[lower]
+	}
+	if len(forms) != 1 {
+		return "", false
+	}
+
+	for accented := range forms {
+		return accented, true
+	}
+
+	return "", false
+}
+
+func inflectedForms(word string) []string {
+	var forms []string
+	switch {
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"):
+	case strings.HasSuffix(word, "eau"), strings.HasSuffix(word, "eu"):
+		forms = append(forms, word+"s", word+"x")
+	default:
+		forms = append(forms, word+"s")
+	}
+
+	// Past participles and adjectives in -é or -i take a feminine ending.
+	if strings.HasSuffix(word, "é") || strings.HasSuffix(word, "i") {
+		forms = append(forms, word+"e", word+"es")
+	}
+
+	return forms
+}
+
+func isTitleCase(s string, words [][]int) bool {
+	if len(words) < 2 {
+		return false
+	}
+
+	for _, w := range words {
+		word := s[w[0]:w[1]]
+		if utf8.RuneCountInString(word) < 3 {
+			continue
+		}
+
+		first, _ := utf8.DecodeRuneInString(word)
+		if !unicode.IsUpper(first) {
+			return false
+		}
+	}
+
+	return true
+}
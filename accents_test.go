@@ -0,0 +1,32 @@
+package gorldline
+
+import (
+	"testing"
+)
+
+func TestAccentRestorer(t *testing.T) {
+	cases := map[string]string{
+		"Puree de carottes":       "Purée de carottes",
+		"Creme brulee":            "Crème brûlée",
+		"Pates carbonara":         "Pâtes carbonara",
+		"Pate de campagne":        "Pâté de campagne",
+		"Poulet epicee":           "Poulet épicée",
+		"Poulet epices":           "Poulet epices",
+		"Poulet epice":            "Poulet epice",
+		"Glace vanille":           "Glace vanille",
+		"Mais grille":             "Mais grillé",
+		"Boeuf saute":             "Bœuf sauté",
+		"ROTI DE PORC":            "RÔTI DE PORC",
+		"Cotes d'agneau grillees": "Côtes d'agneau grillées",
+		"Filet de Dore":           "Filet de Dore",
+		"Poulet Sale Et Poivre":   "Poulet Sale Et Poivre",
+		"Gateau au chocolat":      "Gâteau au chocolat",
+		"Pâtes déjà accentuées":   "Pâtes déjà accentuées",
+	}
+
+	for in, want := range cases {
+		if got := DefaultAccentRestorer.Apply(in); got != want {
+			t.Errorf("Apply(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
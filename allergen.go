@@ -0,0 +1,269 @@
+package gorldline
+
+import (
+	"strings"
+)
+
+const (
+	allergenTagPrefix = "allergen:"
+)
+
+var (
+	AllergenGluten      = &Allergen{"gluten", map[string]string{"fr": "Gluten", "en": "Gluten"}}
+	AllergenCrustaceans = &Allergen{"crustaceans", map[string]string{"fr": "Crustacés", "en": "Crustaceans"}}
+	AllergenEggs        = &Allergen{"eggs", map[string]string{"fr": "Œufs", "en": "Eggs"}}
+	AllergenFish        = &Allergen{"fish", map[string]string{"fr": "Poissons", "en": "Fish"}}
+	AllergenPeanuts     = &Allergen{"peanuts", map[string]string{"fr": "Arachides", "en": "Peanuts"}}
+	AllergenSoy         = &Allergen{"soy", map[string]string{"fr": "Soja", "en": "Soy"}}
+	AllergenMilk        = &Allergen{"milk", map[string]string{"fr": "Lait", "en": "Milk"}}
+	AllergenNuts        = &Allergen{"nuts", map[string]string{"fr": "Fruits à coque", "en": "Nuts"}}
+	AllergenCelery      = &Allergen{"celery", map[string]string{"fr": "Céleri", "en": "Celery"}}
+	AllergenMustard     = &Allergen{"mustard", map[string]string{"fr": "Moutarde", "en": "Mustard"}}
+	AllergenSesame      = &Allergen{"sesame", map[string]string{"fr": "Sésame", "en": "Sesame"}}
+	AllergenSulphites   = &Allergen{"sulphites", map[string]string{"fr": "Sulfites", "en": "Sulphites"}}
+	AllergenLupin       = &Allergen{"lupin", map[string]string{"fr": "Lupin", "en": "Lupin"}}
+	AllergenMolluscs    = &Allergen{"molluscs", map[string]string{"fr": "Mollusques", "en": "Molluscs"}}
+
+	Allergens = []*Allergen{
+		AllergenGluten, AllergenCrustaceans, AllergenEggs, AllergenFish, AllergenPeanuts, AllergenSoy, AllergenMilk,
+		AllergenNuts, AllergenCelery, AllergenMustard, AllergenSesame, AllergenSulphites, AllergenLupin, AllergenMolluscs,
+	}
+
+	// Terms are matched on stemmed words, see StemFrench. An empty list shadows a shorter term ("noix de coco").
+	allergenLexicon = map[string][]string{
+		// Cereals and doughs.
+		"gluten": {"gluten"}, "ble": {"gluten"}, "farine": {"gluten"}, "seigle": {"gluten"}, "orge": {"gluten"},
+		"epeautre": {"gluten"}, "avoine": {"gluten"}, "pain": {"gluten"}, "baguette": {"gluten"}, "chapelure": {"gluten"},
+		"panure": {"gluten"}, "pane": {"gluten"}, "panee": {"gluten"}, "croquette": {"gluten"}, "beignet": {"gluten"},
+		"pates": {"gluten"}, "pate feuilletee": {"gluten"}, "feuillete": {"gluten"}, "croute": {"gluten"},
+		"spaghetti": {"gluten"}, "tagliatelle": {"gluten"}, "penne": {"gluten"}, "macaroni": {"gluten"},
+		"coquillette": {"gluten"}, "fusilli": {"gluten"}, "farfalle": {"gluten"}, "gnocchi": {"gluten"},
+		"ravioli": {"gluten", "eggs"}, "nouille": {"gluten"}, "couscous": {"gluten"}, "semoule": {"gluten"},
+		"boulgour": {"gluten"}, "taboule": {"gluten"}, "pizza": {"gluten", "milk"}, "burger": {"gluten"},
+		"sandwich": {"gluten"}, "wrap": {"gluten"}, "tortilla": {"gluten"}, "nugget": {"gluten"}, "tempura": {"gluten"},
+		"biscuit": {"gluten"}, "cake": {"gluten", "eggs"}, "gateau": {"gluten", "eggs", "milk"},
+		"brioche": {"gluten", "eggs", "milk"}, "croissant": {"gluten", "milk"}, "gaufre": {"gluten", "eggs", "milk"},
+		"crepe": {"gluten", "eggs", "milk"}, "tarte": {"gluten"}, "quiche": {"gluten", "eggs", "milk"},
+		"lasagne": {"gluten", "milk"}, "cannelloni": {"gluten", "milk"}, "croque monsieur": {"gluten", "milk"},
+		"welsh": {"gluten", "milk", "sulphites"}, "cordon bleu": {"gluten", "milk"}, "flammekueche": {"gluten", "milk"},
+		"madeleine": {"gluten", "eggs", "milk"}, "financier": {"gluten", "nuts", "eggs", "milk"},
+		"biere": {"gluten"}, "carbonade": {"gluten"},
+		// Crustaceans.
+		"crustace": {"crustaceans"}, "crevette": {"crustaceans"}, "gamba": {"crustaceans"}, "crabe": {"crustaceans"},
+		"tourteau": {"crustaceans"}, "homard": {"crustaceans"}, "langouste": {"crustaceans"},
+		"langoustine": {"crustaceans"}, "ecrevisse": {"crustaceans"}, "surimi": {"crustaceans", "fish", "eggs"},
+		"fruits de mer": {"crustaceans", "molluscs"}, "paella": {"crustaceans", "molluscs", "fish"},
+		// Eggs.
+		"oeuf": {"eggs"}, "omelette": {"eggs"}, "mayonnaise": {"eggs", "mustard"}, "aioli": {"eggs"},
+		"meringue": {"eggs"}, "flan": {"eggs", "milk"}, "clafoutis": {"eggs", "milk", "gluten"},
+		"ile flottante": {"eggs", "milk"}, "creme brulee": {"eggs", "milk"}, "creme caramel": {"eggs", "milk"},
+		"mousse au chocolat": {"eggs", "milk"}, "tiramisu": {"eggs", "milk", "gluten"}, "carbonara": {"eggs", "milk"},
+		"bearnaise": {"eggs", "milk"}, "hollandaise": {"eggs", "milk"}, "pates fraiches": {"gluten", "eggs"},
+		// Fish.
+		"poisson": {"fish"}, "saumon": {"fish"}, "thon": {"fish"}, "cabillaud": {"fish"}, "colin": {"fish"},
+		"merlu": {"fish"}, "merlan": {"fish"}, "lieu noir": {"fish"}, "lieu jaune": {"fish"}, "sole": {"fish"},
+		"dorade": {"fish"}, "daurade": {"fish"}, "truite": {"fish"}, "sardine": {"fish"}, "maquereau": {"fish"},
+		"hareng": {"fish"}, "anchois": {"fish"}, "limande": {"fish"}, "fletan": {"fish"}, "eglefin": {"fish"},
+		"haddock": {"fish"}, "lotte": {"fish"}, "raie": {"fish"}, "rouget": {"fish"}, "tilapia": {"fish"},
+		"pangasius": {"fish"}, "hoki": {"fish"}, "brandade": {"fish", "milk"}, "bouillabaisse": {"fish", "crustaceans"},
+		"fish": {"fish"}, "nuoc mam": {"fish"},
+		// Peanuts.
+		"arachide": {"peanuts"}, "cacahuete": {"peanuts"}, "cacahouete": {"peanuts"}, "satay": {"peanuts"},
+		"mafe": {"peanuts"},
+		// Soy.
+		"soja": {"soy"}, "tofu": {"soy"}, "tempeh": {"soy"}, "edamame": {"soy"}, "miso": {"soy"}, "tamari": {"soy"},
+		// Milk.
+		"lait": {"milk"}, "lactose": {"milk"}, "laitage": {"milk"}, "creme": {"milk"}, "chantilly": {"milk"},
+		"beurre": {"milk"}, "fromage": {"milk"}, "yaourt": {"milk"}, "yogourt": {"milk"}, "emmental": {"milk"},
+		"gruyere": {"milk"}, "comte": {"milk"}, "mozzarella": {"milk"}, "parmesan": {"milk"}, "chevre": {"milk"},
+		"feta": {"milk"}, "raclette": {"milk"}, "reblochon": {"milk"}, "tartiflette": {"milk"}, "camembert": {"milk"},
+		"brie": {"milk"}, "roquefort": {"milk"}, "cheddar": {"milk"}, "mascarpone": {"milk"}, "ricotta": {"milk"},
+		"cheese": {"milk"}, "bechamel": {"milk", "gluten"}, "mornay": {"milk", "gluten"}, "gratin": {"milk"},
+		"gratine": {"milk"}, "puree": {"milk"}, "glace": {"milk"}, "fondue": {"milk"}, "panna cotta": {"milk"},
+		"riz au lait": {"milk"}, "beurre blanc": {"milk", "sulphites"},
+		"beurre de cacahuete": {"peanuts"}, "lait de coco": {}, "creme de marron": {},
+		// Nuts.
+		"noix": {"nuts"}, "noisette": {"nuts"}, "amande": {"nuts"}, "pistache": {"nuts"}, "cajou": {"nuts"},
+		"pecan": {"nuts"}, "macadamia": {"nuts"}, "praline": {"nuts"}, "nougat": {"nuts"}, "frangipane": {"nuts", "eggs"},
+		"noix de coco": {}, "noix de muscade": {}, "noix de saint jacques": {"molluscs"}, "noix de veau": {},
+		// Celery.
+		"celeri": {"celery"}, "celeri remoulade": {"celery", "eggs", "mustard"},
+		// Mustard.
+		"moutarde": {"mustard"}, "dijonnaise": {"mustard", "eggs"}, "vinaigrette": {"mustard", "sulphites"},
+		"remoulade": {"mustard", "eggs"},
+		// Sesame.
+		"sesame": {"sesame"}, "tahini": {"sesame"}, "tahin": {"sesame"}, "houmous": {"sesame"}, "hummus": {"sesame"},
+		"halva": {"sesame"},
+		// Sulphites.
+		"sulfite": {"sulphites"}, "vin": {"sulphites"}, "vinaigre": {"sulphites"}, "cidre": {"sulphites"},
+		"porto": {"sulphites"}, "madere": {"sulphites"}, "bourguignon": {"sulphites"}, "coq au vin": {"sulphites"},
+		"abricots secs": {"sulphites"}, "raisins secs": {"sulphites"}, "pruneau": {"sulphites"},
+		"choucroute": {"sulphites"}, "cornichon": {"sulphites"}, "mariniere": {"sulphites"},
+		// Lupin.
+		"lupin": {"lupin"},
+		// Molluscs.
+		"mollusque": {"molluscs"}, "moule": {"molluscs"}, "huitre": {"molluscs"}, "calamar": {"molluscs"},
+		"calmar": {"molluscs"}, "encornet": {"molluscs"}, "seiche": {"molluscs"}, "poulpe": {"molluscs"},
+		"pieuvre": {"molluscs"}, "saint jacques": {"molluscs"}, "escargot": {"molluscs"}, "bulot": {"molluscs"},
+		"palourde": {"molluscs"}, "bigorneau": {"molluscs"},
+	}
+
+	negationWords = []string{"sans"}
+
+	DefaultAllergenTagger = NewAllergenTagger(allergenLexicon)
+)
+
+type Allergen struct {
+	ID    string            `json:"id"`
+	Names map[string]string `json:"names"`
+}
+
+func (a *Allergen) Name(l *Language) string {
+	if l != nil {
+		if n, pres := a.Names[l.Code]; pres {
+			return n
+		}
+	}
+
+	return a.Names[French.Code]
+}
+
+func AllergenByID(id string) *Allergen {
+	for _, a := range Allergens {
+		if a.ID == id {
+			return a
+		}
+	}
+
+	return nil
+}
+
+type AllergenMatch struct {
+	Allergen string   `json:"allergen"`
+	Evidence []string `json:"evidence"`
+}
+
+func NewAllergenTagger(lexicon map[string][]string) *AllergenTagger {
+	t := new(AllergenTagger)
+	t.terms = newTermMatcher()
+
+	for term, allergens := range lexicon {
+		t.AddTerm(term, allergens...)
+	}
+
+	return t
+}
+
+type AllergenTagger struct {
+	terms *termMatcher
+}
+
+func (t *AllergenTagger) AddTerm(term string, allergens ...string) {
+	t.terms.add(term, allergens)
+}
+
+func (t *AllergenTagger) Detect(texts ...string) []*AllergenMatch {
+	evidence := make(map[string][]string)
+	for _, text := range texts {
+		for _, m := range t.terms.find(text) {
+			if m.negated {
+				continue
+			}
+
+			for _, a := range m.values {
+				if !containsString(evidence[a], m.text) {
+					evidence[a] = append(evidence[a], m.text)
+				}
+			}
+		}
+	}
+
+	matches := make([]*AllergenMatch, 0, len(evidence))
+	for _, a := range Allergens {
+		if e, pres := evidence[a.ID]; pres {
+			matches = append(matches, &AllergenMatch{a.ID, e})
+		}
+	}
+
+	return matches
+}
+
+func (t *AllergenTagger) Tag(m *Meal) {
+	for tag := range m.Tags {
+		if strings.HasPrefix(tag, allergenTagPrefix) {
+			m.Tags.Remove(tag)
+		}
+	}
+
+	m.Allergens = t.Detect(m.componentTexts()...)
+	for _, a := range m.Allergens {
+		m.Tags.Add(allergenTagPrefix + a.Allergen)
+	}
+}
+
+func (m *Meal) HasAllergen(id string) bool {
+	return m.Tags.Has(allergenTagPrefix + id)
+}
+
+type termMatch struct {
+	text    string
+	values  []string
+	negated bool
+}
+
+type termMatcher struct {
+	terms   map[string][]string
+	longest int
+}
+
+func newTermMatcher() *termMatcher {
+	m := new(termMatcher)
+	m.terms = make(map[string][]string)
+
+	return m
+}
+
+func (m *termMatcher) add(term string, values []string) {
+	words := termWords(term)
+	if len(words) == 0 {
+		return
+	}
+
+	m.terms[strings.Join(words, " ")] = values
+	if len(words) > m.longest {
+		m.longest = len(words)
+	}
+}
+
+// Greedy longest match on stemmed words, so that "noix de coco" shadows "noix".
+func (m *termMatcher) find(s string) []termMatch {
+	indexes := wordRegex.FindAllStringIndex(s, -1)
+	folded, words := make([]string, len(indexes)), make([]string, len(indexes))
+	for i, w := range indexes {
+		folded[i] = strings.ToLower(foldAccents(s[w[0]:w[1]]))
+		words[i] = StemFrench(s[w[0]:w[1]])
+	}
+
+	matches := make([]termMatch, 0)
+	for i := 0; i < len(words); {
+		matched := 1
+		for n := m.longest; n > 0; n-- {
+			if i+n > len(words) {
+				continue
+			}
+
+			values, pres := m.terms[strings.Join(words[i:i+n], " ")]
+			if !pres {
+				continue
+			}
+
+			negated := i > 0 && containsString(negationWords, folded[i-1])
+			matches = append(matches, termMatch{s[indexes[i][0]:indexes[i+n-1][1]], values, negated})
+			matched = n
+			break
+		}
+		i += matched
+	}
+
+	return matches
+}
@@ -0,0 +1,40 @@
+package gorldline
+
+import (
+	"testing"
+)
+
+func TestAllergenTagger(t *testing.T) {
+	cases := []struct {
+		name   string
+		has    []string
+		hasNot []string
+	}{
+		{"Pâtes bolognaise", []string{"gluten"}, nil},
+		{"Pâté de campagne", nil, []string{"gluten"}},
+		{"Terrine de pâté", nil, []string{"gluten"}},
+		{"Pâtés en croûte", []string{"gluten"}, nil},
+		{"Tarte au citron meringuée", []string{"gluten", "eggs"}, nil},
+		{"Gâteau au chocolat", []string{"gluten", "eggs", "milk"}, nil},
+		{"Curry de poulet au lait de coco", nil, []string{"milk", "nuts"}},
+		{"Noix de Saint-Jacques", []string{"molluscs"}, []string{"nuts"}},
+		{"Salade aux noix", []string{"nuts"}, nil},
+		{"Moules frites", []string{"molluscs"}, nil},
+		{"Filet de colin, crevettes", []string{"fish", "crustaceans"}, nil},
+		{"Carottes râpées", nil, []string{"gluten", "milk", "eggs"}},
+	}
+
+	for _, c := range cases {
+		m := taggedMeal(c.name, CategoryDailyDish.ID)
+		for _, id := range c.has {
+			if !m.HasAllergen(id) {
+				t.Errorf("%q: missing allergen %s", c.name, id)
+			}
+		}
+		for _, id := range c.hasNot {
+			if m.HasAllergen(id) {
+				t.Errorf("%q: unexpected allergen %s", c.name, id)
+			}
+		}
+	}
+}
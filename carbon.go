@@ -0,0 +1,274 @@
+package gorldline
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+const (
+	ProteinBeef       = "beef"
+	ProteinPork       = "pork"
+	ProteinPoultry    = "poultry"
+	ProteinFish       = "fish"
+	ProteinVegetarian = "vegetarian"
+
+	CarbonLow    = "low"
+	CarbonMedium = "medium"
+	CarbonHigh   = "high"
+
+	carbonMediumThreshold = 1.0
+	carbonHighThreshold   = 2.5
+
+	proteinImplied = "implied"
+)
+
+var (
+	// Approximate kg CO2e for a canteen portion, rounded from the Agribalyse reference dishes.
+	carbonReference = map[string]float64{
+		ProteinBeef:       7.0,
+		ProteinPork:       1.6,
+		ProteinPoultry:    1.2,
+		ProteinFish:       1.5,
+		ProteinVegetarian: 0.5,
+	}
+
+	// Proteins with the highest impact first, a dish with both beef and chicken counts as beef.
+	proteinPriority = []string{ProteinBeef, ProteinPork, ProteinFish, ProteinPoultry, ProteinVegetarian}
+
+	// Ruminant meat (veal, lamb) is counted as beef, its footprint being of the same order.
+	proteinLexicon = map[string]string{
+		"boeuf": ProteinBeef, "veau": ProteinBeef, "agneau": ProteinBeef, "mouton": ProteinBeef, "bavette": ProteinBeef,
+		"entrecote": ProteinBeef, "merguez": ProteinBeef,
+		"porc": ProteinPork, "cochon": ProteinPork, "jambon": ProteinPork, "lardon": ProteinPork, "bacon": ProteinPork,
+		"saucisse": ProteinPork, "chipolata": ProteinPork, "chorizo": ProteinPork, "andouillette": ProteinPork,
+		"boudin": ProteinPork, "echine": ProteinPork, "travers": ProteinPork, "pâté": ProteinPork,
+		"poulet": ProteinPoultry, "dinde": ProteinPoultry, "canard": ProteinPoultry, "volaille": ProteinPoultry,
+		"pintade": ProteinPoultry, "caille": ProteinPoultry,
+		"saucisse de volaille": ProteinPoultry, "jambon de dinde": ProteinPoultry,
+		"poisson": ProteinFish, "saumon": ProteinFish, "thon": ProteinFish, "cabillaud": ProteinFish,
+		"colin": ProteinFish, "merlu": ProteinFish, "lieu": ProteinFish, "truite": ProteinFish, "moule": ProteinFish,
+		"crevette": ProteinFish, "calamar": ProteinFish, "fruits de mer": ProteinFish,
+	}
+
+	// Dishes whose usual recipe implies a protein, an explicit one wins: "Lasagnes de saumon" is fish.
+	proteinDishLexicon = map[string]string{
+		"steak": ProteinBeef, "steak hache": ProteinBeef, "bourguignon": ProteinBeef, "hachis parmentier": ProteinBeef,
+		"parmentier": ProteinBeef, "blanquette": ProteinBeef, "burger": ProteinBeef, "bolognaise": ProteinBeef,
+		"chili con carne": ProteinBeef, "carbonade": ProteinBeef, "pot au feu": ProteinBeef, "osso buco": ProteinBeef,
+		"kebab": ProteinBeef, "lasagne": ProteinBeef, "moussaka": ProteinBeef,
+		"choucroute": ProteinPork, "cassoulet": ProteinPork, "tartiflette": ProteinPork,
+		"nugget": ProteinPoultry, "paella": ProteinFish, "nicoise": ProteinFish,
+		// Plant staples rate a dish as vegetarian when nothing else is named, butter or cheese barely change it.
+		"legume": ProteinVegetarian, "lentille": ProteinVegetarian, "pois chiche": ProteinVegetarian,
+		"haricot": ProteinVegetarian, "petits pois": ProteinVegetarian, "riz": ProteinVegetarian,
+		"semoule": ProteinVegetarian, "boulgour": ProteinVegetarian, "quinoa": ProteinVegetarian,
+		"frite": ProteinVegetarian, "pomme de terre": ProteinVegetarian, "salade": ProteinVegetarian,
+		"carotte": ProteinVegetarian, "tofu": ProteinVegetarian, "falafel": ProteinVegetarian,
+		"ratatouille": ProteinVegetarian, "curry de legume": ProteinVegetarian,
+	}
+
+	carbonLevelNames = map[string]map[string]string{
+		CarbonLow:    {"fr": "faible", "en": "low", "de": "niedrig", "es": "bajo"},
+		CarbonMedium: {"fr": "moyen", "en": "medium", "de": "mittel", "es": "medio"},
+		CarbonHigh:   {"fr": "élevé", "en": "high", "de": "hoch", "es": "alto"},
+	}
+
+	carbonLevelColors = map[string]color.RGBA{
+		CarbonLow:    {0x2e, 0x7d, 0x32, 0xff},
+		CarbonMedium: {0xf9, 0xa8, 0x25, 0xff},
+		CarbonHigh:   {0xc6, 0x28, 0x28, 0xff},
+	}
+
+	carbonEmojis = map[string]string{
+		CarbonLow:    "🟢",
+		CarbonMedium: "🟡",
+		CarbonHigh:   "🔴",
+	}
+
+	DefaultCarbonEstimator = NewCarbonEstimator(proteinLexicon, proteinDishLexicon, carbonReference)
+)
+
+type CarbonEstimate struct {
+	Protein string  `json:"protein"`
+	CO2e    float64 `json:"co2e"`
+	Level   string  `json:"level"`
+}
+
+func (c *CarbonEstimate) LevelName(l *Language) string {
+	if l == nil {
+		l = French
+	}
+
+	return carbonLevelNames[c.Level][l.Code]
+}
+
+func (c *CarbonEstimate) Badge() string {
+	return "CO₂ " + c.LevelName(French)
+}
+
+func (c *CarbonEstimate) Color() string {
+	rgba := carbonLevelColors[c.Level]
+	return fmt.Sprintf("#%02x%02x%02x", rgba.R, rgba.G, rgba.B)
+}
+
+func (c *CarbonEstimate) String() string {
+	return fmt.Sprintf("Impact carbone %s (%s kg CO2e)", c.LevelName(French), strings.Replace(fmt.Sprintf("%.1f", c.CO2e), ".", ",", 1))
+}
+
+type CarbonSummary struct {
+	Dishes  int            `json:"dishes"`
+	Total   float64        `json:"total"`
+	Average float64        `json:"average"`
+	Levels  map[string]int `json:"levels"`
+}
+
+type CarbonEstimator struct {
+	terms     *termMatcher
+	reference map[string]float64
+}
+
+func NewCarbonEstimator(lexicon, dishes map[string]string, reference map[string]float64) *CarbonEstimator {
+	e := new(CarbonEstimator)
+	e.terms = newTermMatcher()
+	e.reference = reference
+
+	for term, protein := range lexicon {
+		e.terms.add(term, []string{protein})
+	}
+	for term, protein := range dishes {
+		e.terms.add(term, []string{protein, proteinImplied})
+	}
+
+	return e
+}
+
+// The main component decides when it names a protein, "Salade de lentilles, lardons" is mostly lentils.
+// Only dishes without one fall back to their sauce and sides, "Pâtes sauce au thon" is a fish dish.
+// The protein implied by a dish name comes last, "Lasagnes aux légumes" are not beef.
+func (e *CarbonEstimator) Protein(m *Meal) string {
+	var mainImplied string
+	if m.Components != nil {
+		explicit, implied := e.findProtein(m.Components.Main)
+		if explicit != "" {
+			return explicit
+		}
+		mainImplied = implied
+	}
+	explicit, implied := e.findProtein(m.Name)
+	if explicit != "" {
+		return explicit
+	}
+
+	if m.HasDiet(DietVegetarian) {
+		return ProteinVegetarian
+	}
+	if mainImplied != "" {
+		return mainImplied
+	}
+	if implied != "" {
+		return implied
+	}
+	if m.HasAllergen(AllergenFish.ID) || m.HasAllergen(AllergenCrustaceans.ID) || m.HasAllergen(AllergenMolluscs.ID) {
+		return ProteinFish
+	}
+
+	return ""
+}
+
+func (e *CarbonEstimator) findProtein(s string) (string, string) {
+	explicit := make(map[string]bool)
+	implied := make(map[string]bool)
+	for _, match := range e.terms.find(s) {
+		if match.negated {
+			continue
+		}
+		if len(match.values) > 1 && match.values[1] == proteinImplied {
+			implied[match.values[0]] = true
+		} else {
+			explicit[match.values[0]] = true
+		}
+	}
+
+	return highestProtein(explicit), highestProtein(implied)
+}
+
+func highestProtein(found map[string]bool) string {
+	for _, p := range proteinPriority {
+		if found[p] {
+			return p
+		}
+	}
+
+	return ""
+}
+
+func (e *CarbonEstimator) Estimate(m *Meal) *CarbonEstimate {
+	protein := e.Protein(m)
+	co2e, pres := e.reference[protein]
+	if !pres {
+		return nil
+	}
+
+	c := new(CarbonEstimate)
+	c.Protein = protein
+	c.CO2e = co2e
+	c.Level = carbonLevel(co2e)
+
+	return c
+}
+
+func carbonLevel(co2e float64) string {
+	switch {
+	case co2e >= carbonHighThreshold:
+		return CarbonHigh
+	case co2e >= carbonMediumThreshold:
+		return CarbonMedium
+	default:
+		return CarbonLow
+	}
+}
+
+// Only main dishes are counted, sides and desserts would dilute the comparison between stations.
+func summarizeCarbon(days []*Day) *CarbonSummary {
+	s := new(CarbonSummary)
+	s.Levels = make(map[string]int)
+
+	for _, d := range days {
+		for _, c := range d.OrderedCategories() {
+			if c.IsSide() {
+				continue
+			}
+
+			for _, m := range d.Meals[c.Label] {
+				if m.Carbon == nil {
+					continue
+				}
+
+				s.Dishes++
+				s.Total += m.Carbon.CO2e
+				s.Levels[m.Carbon.Level]++
+			}
+		}
+	}
+
+	if s.Dishes > 0 {
+		s.Average = roundTenth(s.Total / float64(s.Dishes))
+	}
+	s.Total = roundTenth(s.Total)
+
+	return s
+}
+
+func (d *Day) Carbon() *CarbonSummary {
+	return summarizeCarbon([]*Day{d})
+}
+
+func (w *Week) Carbon() (*CarbonSummary, error) {
+	days, err := w.GetDays()
+	if err != nil {
+		return nil, err
+	}
+
+	return summarizeCarbon(days), nil
+}
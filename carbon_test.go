@@ -0,0 +1,54 @@
+package gorldline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCarbonProtein(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"Lasagnes", ProteinBeef},
+		{"Lasagnes de saumon", ProteinFish},
+		{"Lasagnes aux légumes", ProteinVegetarian},
+		{"Steak frites", ProteinBeef},
+		{"Steak de thon, riz", ProteinFish},
+		{"Burger de poulet", ProteinPoultry},
+		{"Burger végétarien", ProteinVegetarian},
+		{"Blanquette de veau", ProteinBeef},
+		{"Blanquette de dinde", ProteinPoultry},
+		{"Poulet basquaise, riz", ProteinPoultry},
+		{"Salade de lentilles, lardons", ProteinPork},
+		{"Lentilles", ProteinVegetarian},
+		{"Salade niçoise", ProteinFish},
+		{"Pâtes sauce au thon", ProteinFish},
+		{"Saucisse de volaille, purée", ProteinPoultry},
+	}
+
+	for _, c := range cases {
+		m := taggedMeal(c.name, CategoryDailyDish.ID)
+		if got := DefaultCarbonEstimator.Protein(m); got != c.want {
+			t.Errorf("Protein(%q) = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCarbonSummaryMainDishes(t *testing.T) {
+	d := &Day{Start: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), Meals: map[string][]*Meal{
+		"Plat du jour": {{Name: "Bœuf bourguignon", Tags: make(Tags)}},
+		"Grill":        {{Name: "Cuisse de poulet", Tags: make(Tags)}},
+		"Dessert":      {{Name: "Compote de pommes", Tags: make(Tags)}},
+		"Légumes":      {{Name: "Carottes râpées", Tags: make(Tags)}},
+	}}
+	d.tagMeals()
+
+	s := d.Carbon()
+	if s.Dishes != 2 {
+		t.Fatalf("Dishes = %d, want 2", s.Dishes)
+	}
+	if want := roundTenth((carbonReference[ProteinBeef] + carbonReference[ProteinPoultry]) / 2); s.Average != want {
+		t.Errorf("Average = %v, want %v", s.Average, want)
+	}
+}
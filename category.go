@@ -0,0 +1,165 @@
+package gorldline
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	unknownCategoryOrder = 1000
+)
+
+var (
+	CategoryStarter = &Category{
+		ID:      "starter",
+		Names:   map[string]string{"fr": "Entrées", "en": "Starters", "de": "Vorspeisen", "es": "Entrantes"},
+		Order:   10,
+		Aliases: []string{"Entrées", "Entrée", "Hors d'oeuvre", "Hors d'oeuvres"},
+	}
+	CategoryDailyDish = &Category{
+		ID:      "daily-dish",
+		Names:   map[string]string{"fr": "Plat du Jour", "en": "Dish of the Day", "de": "Tagesgericht", "es": "Plato del día"},
+		Order:   20,
+		Aliases: []string{"Plat du Jour", "PDJ", "Plat"},
+	}
+	CategoryTrattoria = &Category{
+		ID:      "trattoria",
+		Names:   map[string]string{"fr": "Trattoria", "en": "Trattoria", "de": "Trattoria", "es": "Trattoria"},
+		Order:   30,
+		Aliases: []string{"Trattoria", "Pizza", "Pâtes", "Pasta"},
+	}
+	CategoryWorld = &Category{
+		ID:      "world",
+		Names:   map[string]string{"fr": "Cuisine du Monde", "en": "World Cuisine", "de": "Weltküche", "es": "Cocina del mundo"},
+		Order:   40,
+		Aliases: []string{"Cuisine du Monde", "Monde"},
+	}
+	CategoryGrill = &Category{
+		ID:      "grill",
+		Names:   map[string]string{"fr": "Grill", "en": "Grill", "de": "Grill", "es": "Parrilla"},
+		Order:   50,
+		Aliases: []string{"Grill", "Grillade", "Grillades"},
+	}
+	CategoryVegetableBar = &Category{
+		ID:      "vegetable-bar",
+		Names:   map[string]string{"fr": "Bar à Légumes", "en": "Vegetable Bar", "de": "Gemüsebar", "es": "Barra de verduras"},
+		Order:   60,
+		Aliases: []string{"Bar à Légumes", "Légumes", "Accompagnements", "Garnitures"},
+	}
+	CategoryCheese = &Category{
+		ID:      "cheese",
+		Names:   map[string]string{"fr": "Fromages", "en": "Cheese", "de": "Käse", "es": "Quesos"},
+		Order:   70,
+		Aliases: []string{"Fromage", "Fromages", "Laitages", "Produits Laitiers"},
+	}
+	CategoryDessert = &Category{
+		ID:      "dessert",
+		Names:   map[string]string{"fr": "Desserts", "en": "Desserts", "de": "Desserts", "es": "Postres"},
+		Order:   80,
+		Aliases: []string{"Dessert", "Desserts"},
+	}
+
+	// Side stations are picked on the way to a main dish, they are not a reason to choose a queue.
+	sideCategories = []*Category{CategoryStarter, CategoryVegetableBar, CategoryCheese, CategoryDessert}
+
+	Categories = []*Category{
+		CategoryStarter,
+		CategoryDailyDish,
+		CategoryTrattoria,
+		CategoryWorld,
+		CategoryGrill,
+		CategoryVegetableBar,
+		CategoryCheese,
+		CategoryDessert,
+	}
+)
+
+type Category struct {
+	ID      string            `json:"id"`
+	Names   map[string]string `json:"names"`
+	Order   int               `json:"order"`
+	Aliases []string          `json:"-"`
+}
+
+func (c *Category) Name(l *Language) string {
+	if l != nil {
+		if n, pres := c.Names[l.Code]; pres {
+			return n
+		}
+	}
+
+	return c.Names[French.Code]
+}
+
+func (c *Category) IsSide() bool {
+	for _, s := range sideCategories {
+		if c.ID == s.ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Category) matches(label string) bool {
+	key := categoryKey(label)
+	for _, a := range c.Aliases {
+		if categoryKey(a) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func CategoryByLabel(label string) *Category {
+	for _, c := range Categories {
+		if c.matches(label) {
+			return c
+		}
+	}
+
+	c := new(Category)
+	c.ID = categoryKey(label)
+	c.ID = strings.ReplaceAll(c.ID, " ", "-")
+	c.Names = map[string]string{French.Code: label}
+	c.Order = unknownCategoryOrder
+	c.Aliases = []string{label}
+
+	return c
+}
+
+func CategoryByID(id string) *Category {
+	for _, c := range Categories {
+		if c.ID == id {
+			return c
+		}
+	}
+
+	return nil
+}
+
+type DayCategory struct {
+	*Category
+	Label string `json:"label"`
+}
+
+func categorize(meals map[string][]*Meal) []*DayCategory {
+	categories := make([]*DayCategory, 0, len(meals))
+	for label := range meals {
+		categories = append(categories, &DayCategory{CategoryByLabel(label), label})
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Order != categories[j].Order {
+			return categories[i].Order < categories[j].Order
+		}
+		return categories[i].Label < categories[j].Label
+	})
+
+	return categories
+}
+
+func categoryKey(label string) string {
+	return strings.ToLower(foldAccents(strings.Join(strings.Fields(label), " ")))
+}
@@ -1,16 +1,830 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"math"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/scotow/gorldline"
 )
 
-func handleCurrentWeek(w http.ResponseWriter, _ *http.Request) {
+const (
+	defaultPollClosing = "11:30"
+	pollEventsInterval = 2 * time.Second
+	feedCacheTTL       = 10 * time.Minute
+)
+
+var (
+	translators map[string]*gorldline.Translator
+	profiles    *gorldline.ProfileStore
+	ratings     *gorldline.RatingStore
+	polls       *gorldline.PollStore
+	notifier    gorldline.Notifier
+	webhooks    *gorldline.WebhookNotifier
+	feeds       = &feedCache{feeds: make(map[bool]*cachedFeed)}
+	// Polls are created for the current day of the restaurant, whatever the timezone of the server.
+	pollLocation *time.Location
+)
+
+func init() {
+	l, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		l = time.Local
+	}
+
+	pollLocation = l
+}
+
+func handleCurrentWeek(w http.ResponseWriter, r *http.Request) {
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	nearestWeek := list.Nearest()
+	if nearestWeek == nil {
+		http.Error(w, "no menu available", http.StatusBadGateway)
+		log.Println("no menu available")
+		return
+	}
+
+	err = nearestWeek.FetchDaysIfNeeded()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	keep, err := mealFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	week := nearestWeek
+	if keep != nil {
+		week, err = nearestWeek.Filter(keep)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			log.Println(err)
+			return
+		}
+	}
+
+	enrich(r, week.Days)
+	writeJson(week, w)
+}
+
+func handleCurrentDay(w http.ResponseWriter, r *http.Request) {
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	nearestWeek := list.Nearest()
+	if nearestWeek == nil {
+		http.Error(w, "no menu available", http.StatusBadGateway)
+		log.Println("no menu available")
+		return
+	}
+
+	nearestDay, err := nearestWeek.Nearest()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	keep, err := mealFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if nearestDay != nil {
+		if keep != nil {
+			nearestDay = nearestDay.Filter(keep)
+		}
+		enrich(r, []*gorldline.Day{nearestDay})
+	}
+	writeJson(nearestDay, w)
+}
+
+func handleMeal(w http.ResponseWriter, r *http.Request) {
+	meal := findMeal(w, r)
+	if meal == nil {
+		return
+	}
+
+	meal.Rating = ratings.Summary(meal.Name)
+	writeJson(meal, w)
+}
+
+func findMeal(w http.ResponseWriter, r *http.Request) *gorldline.Meal {
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return nil
+	}
+
+	meal, err := list.Meal(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return nil
+	}
+
+	if meal == nil {
+		http.Error(w, "meal not found", http.StatusNotFound)
+		return nil
+	}
+
+	return meal
+}
+
+func handleGetRatings(w http.ResponseWriter, r *http.Request) {
+	meal := findMeal(w, r)
+	if meal == nil {
+		return
+	}
+
+	writeJson(struct {
+		Summary *gorldline.RatingSummary `json:"summary"`
+		Reviews []*gorldline.Review      `json:"reviews"`
+	}{ratings.Summary(meal.Name), ratings.Reviews(meal.Name)}, w)
+}
+
+func handlePostRating(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Stars   int    `json:"stars"`
+		Comment string `json:"comment"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		http.Error(w, "invalid rating body", http.StatusBadRequest)
+		return
+	}
+
+	meal := findMeal(w, r)
+	if meal == nil {
+		return
+	}
+
+	review, err := ratings.Rate(meal, r.Header.Get("X-User-Token"), body.Stars, body.Comment, time.Now())
+	switch err {
+	case nil:
+	case gorldline.ErrInvalidRating, gorldline.ErrMissingUserToken:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case gorldline.ErrRatingClosed:
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case gorldline.ErrAlreadyRated:
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	writeJsonStatus(review, http.StatusCreated, w)
+}
+
+func handleCurrentWeekCarbon(w http.ResponseWriter, _ *http.Request) {
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	nearestWeek := list.Nearest()
+	if nearestWeek == nil {
+		http.Error(w, "no menu available", http.StatusBadGateway)
+		log.Println("no menu available")
+		return
+	}
+
+	summary, err := nearestWeek.Carbon()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	days := make(map[string]*gorldline.CarbonSummary, len(nearestWeek.Days))
+	for _, d := range nearestWeek.Days {
+		days[d.ID()] = d.Carbon()
+	}
+
+	writeJson(struct {
+		Week *gorldline.CarbonSummary            `json:"week"`
+		Days map[string]*gorldline.CarbonSummary `json:"days"`
+	}{summary, days}, w)
+}
+
+func handleSearch(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := &gorldline.SearchQuery{
+		Text:     params.Get("q"),
+		Category: params.Get("category"),
+		Limit:    50,
+	}
+
+	var err error
+	if from := params.Get("from"); from != "" {
+		query.From, err = gorldline.ParseDayID(from)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if to := params.Get("to"); to != "" {
+		query.To, err = gorldline.ParseDayID(to)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if minPrice := params.Get("min"); minPrice != "" {
+		p, ok := gorldline.ParsePrice(minPrice)
+		if !ok {
+			http.Error(w, "invalid minimum price", http.StatusBadRequest)
+			return
+		}
+		query.MinPrice = &p
+	}
+	if maxPrice := params.Get("max"); maxPrice != "" {
+		p, ok := gorldline.ParsePrice(maxPrice)
+		if !ok {
+			http.Error(w, "invalid maximum price", http.StatusBadRequest)
+			return
+		}
+		query.MaxPrice = &p
+	}
+	if limit, err := strconv.Atoi(params.Get("limit")); err == nil && limit > 0 {
+		query.Limit = limit
+	}
+
+	index := gorldline.NewSearchIndex()
+	if path, set := os.LookupEnv("HISTORY_FILE"); set {
+		days, err := gorldline.LoadHistoryFile(path)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			log.Println(err)
+			return
+		}
+		index.AddDays(days)
+	}
+
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	err = index.AddList(list)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	writeJson(index.Search(query), w)
+}
+
+func authorizeProfile(w http.ResponseWriter, r *http.Request) *gorldline.Profile {
+	profile, err := profiles.Authorize(mux.Vars(r)["id"], r.Header.Get("X-Profile-Token"))
+	switch err {
+	case nil:
+		return profile
+	case gorldline.ErrProfileNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case gorldline.ErrInvalidProfileToken:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+	}
+
+	return nil
+}
+
+func handleGetProfile(w http.ResponseWriter, r *http.Request) {
+	profile := authorizeProfile(w, r)
+	if profile == nil {
+		return
+	}
+
+	writeJson(profile.Public(), w)
+}
+
+// Creating a profile returns its token once, it is required for every later access.
+func handlePutProfile(w http.ResponseWriter, r *http.Request) {
+	profile := new(gorldline.Profile)
+	err := json.NewDecoder(r.Body).Decode(profile)
+	if err != nil {
+		http.Error(w, gorldline.ErrInvalidProfile.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = webhooks.Validate(profile.Webhook)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	profile.ID = mux.Vars(r)["id"]
+	var token string
+	existing, err := profiles.Get(profile.ID)
+	if err == nil {
+		if !existing.CheckToken(r.Header.Get("X-Profile-Token")) {
+			http.Error(w, gorldline.ErrInvalidProfileToken.Error(), http.StatusUnauthorized)
+			return
+		}
+		profile.Notified = existing.Notified
+		profile.TokenHash = existing.TokenHash
+	} else {
+		token, err = gorldline.NewProfileToken()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			log.Println(err)
+			return
+		}
+		profile.Notified = nil
+		profile.SetToken(token)
+	}
+
+	err = profiles.Put(profile)
+	if err == gorldline.ErrInvalidProfile {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	if token == "" {
+		writeJson(profile.Public(), w)
+		return
+	}
+
+	writeJsonStatus(struct {
+		*gorldline.Profile
+		Token string `json:"token"`
+	}{profile.Public(), token}, http.StatusCreated, w)
+}
+
+func handleDeleteProfile(w http.ResponseWriter, r *http.Request) {
+	profile := authorizeProfile(w, r)
+	if profile == nil {
+		return
+	}
+
+	err := profiles.Delete(profile.ID)
+	if err == gorldline.ErrProfileNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleProfileMatches(w http.ResponseWriter, r *http.Request) {
+	profile := authorizeProfile(w, r)
+	if profile == nil {
+		return
+	}
+
+	days, err := upcomingDays()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	writeJson(profile.Matches(days), w)
+}
+
+// Alerts are normally sent by the ALERT_INTERVAL ticker, the route only exists when ADMIN_TOKEN is set.
+func handleAlerts(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("X-Admin-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(os.Getenv("ADMIN_TOKEN"))) != 1 {
+		http.Error(w, "invalid admin token", http.StatusUnauthorized)
+		return
+	}
+
+	err := sendAlerts()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func upcomingDays() ([]*gorldline.Day, error) {
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		return nil, err
+	}
+
+	return list.UpcomingDays(time.Now())
+}
+
+func sendAlerts() error {
+	days, err := upcomingDays()
+	if err != nil {
+		return err
+	}
+
+	return profiles.Alert(days, notifier)
+}
+
+func handleCreatePoll(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Title  string   `json:"title"`
+		Times  []string `json:"times"`
+		Closes string   `json:"closes"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		http.Error(w, "invalid poll", http.StatusBadRequest)
+		return
+	}
+
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	now := time.Now().In(pollLocation)
+	day, err := list.Day(now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+	if day == nil {
+		http.Error(w, "no menu today", http.StatusNotFound)
+		return
+	}
+
+	if body.Closes == "" {
+		body.Closes = defaultPollClosing
+	}
+	closes, err := gorldline.AtClock(day.Start, body.Closes)
+	if err != nil {
+		http.Error(w, "invalid closing time", http.StatusBadRequest)
+		return
+	}
+	if !closes.After(now) {
+		http.Error(w, "closing time already passed", http.StatusBadRequest)
+		return
+	}
+
+	poll, err := gorldline.NewPoll(day, body.Title, body.Times, closes)
+	if err == gorldline.ErrEmptyPoll || err == gorldline.ErrInvalidDepartureTime {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	err = polls.Create(poll)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	writeJsonStatus(poll.Results(now), http.StatusCreated, w)
+}
+
+func handleGetPoll(w http.ResponseWriter, r *http.Request) {
+	poll, err := polls.Get(mux.Vars(r)["id"])
+	if err == gorldline.ErrPollNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	writeJson(poll.Results(time.Now()), w)
+}
+
+func handleVotePoll(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name   string `json:"name"`
+		Option string `json:"option"`
+		Time   string `json:"time"`
+	}
+	err := json.NewDecoder(r.Body).Decode(&body)
+	if err != nil {
+		http.Error(w, "invalid vote", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	poll, err := polls.Vote(mux.Vars(r)["id"], r.Header.Get("X-User-Token"), body.Name, body.Option, body.Time, now)
+	switch err {
+	case nil:
+	case gorldline.ErrPollNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case gorldline.ErrPollClosed:
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case gorldline.ErrMissingUserToken, gorldline.ErrInvalidPollOption, gorldline.ErrInvalidDepartureTime:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	writeJson(poll.Results(now), w)
+}
+
+// Results are pushed as server-sent events whenever they change, until the poll closes.
+func handlePollEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	_, err := polls.Get(id)
+	if err == gorldline.ErrPollNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ticker := time.NewTicker(pollEventsInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		// The stream has started, errors can only be reported as events.
+		poll, err := polls.Get(id)
+		if err != nil {
+			_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", err)
+			flusher.Flush()
+			log.Println(err)
+			return
+		}
+
+		results := poll.Results(time.Now())
+		data, err := json.Marshal(results)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		if !bytes.Equal(data, last) {
+			_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+			last = data
+		}
+
+		if results.Closed {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func handlePriceCodes(w http.ResponseWriter, _ *http.Request) {
+	writeJson(gorldline.PriceCodes(), w)
+}
+
+func handleQuote(w http.ResponseWriter, r *http.Request) {
+	date, err := gorldline.ParseDayID(mux.Vars(r)["date"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	day, err := list.Day(date)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	if day == nil {
+		http.Error(w, "day not found", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	ids := append(query["meal"], query.Get("main"), query.Get("side"), query.Get("dessert"))
+	meals := make([]*gorldline.Meal, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+
+		meal := day.Meal(id)
+		if meal == nil {
+			http.Error(w, "meal not found: "+id, http.StatusNotFound)
+			return
+		}
+		meals = append(meals, meal)
+	}
+
+	quote, err := gorldline.DefaultSubsidyRules.Quote(meals...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJson(quote, w)
+}
+
+func handleCurrentDayFr(w http.ResponseWriter, _ *http.Request) {
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	nearestWeek := list.Nearest()
+	if nearestWeek == nil {
+		http.Error(w, "no menu available", http.StatusBadGateway)
+		log.Println("no menu available")
+		return
+	}
+
+	nearestDay, err := nearestWeek.Nearest()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	if nearestDay == nil {
+		http.Error(w, "cannot find nearest day", http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	if nearestDay.Start.After(time.Now()) || nearestDay.End.Before(time.Now()) {
+		_, _ = w.Write([]byte("Ce menu ne correspond pas au menu d'aujourd'hui.\n"))
+		_, _ = w.Write([]byte(nearestDay.FrenchSentence(false)))
+	} else {
+		_, _ = w.Write([]byte(nearestDay.FrenchSentence(true)))
+	}
+}
+
+func mealFilter(r *http.Request) (func(*gorldline.Meal) bool, error) {
+	diets := make([]string, 0)
+	for _, param := range r.URL.Query()["diet"] {
+		for _, diet := range strings.Split(param, ",") {
+			diet = strings.TrimSpace(diet)
+			if diet == "" {
+				continue
+			}
+			if !gorldline.IsDiet(diet) {
+				return nil, errors.New("unknown diet: " + diet)
+			}
+			diets = append(diets, diet)
+		}
+	}
+
+	excluded := make([]string, 0)
+	for _, param := range r.URL.Query()["without"] {
+		for _, id := range strings.Split(param, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+			if gorldline.AllergenByID(id) == nil {
+				return nil, errors.New("unknown allergen: " + id)
+			}
+			excluded = append(excluded, id)
+		}
+	}
+
+	ingredients := r.URL.Query()["ingredient"]
+
+	if len(diets) == 0 && len(excluded) == 0 && len(ingredients) == 0 {
+		return nil, nil
+	}
+
+	return func(m *gorldline.Meal) bool {
+		for _, ingredient := range ingredients {
+			if !m.HasIngredient(ingredient) {
+				return false
+			}
+		}
+		for _, diet := range diets {
+			if !m.HasDiet(diet) {
+				return false
+			}
+		}
+		for _, id := range excluded {
+			if m.HasAllergen(id) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func handleAllergens(w http.ResponseWriter, _ *http.Request) {
+	writeJson(gorldline.Allergens, w)
+}
+
+func enrich(r *http.Request, days []*gorldline.Day) {
+	ratings.Annotate(days)
+
+	if nutrition, _ := strconv.ParseBool(r.URL.Query().Get("nutrition")); nutrition {
+		gorldline.DefaultNutritionEstimator.EstimateDays(days)
+	}
+
+	for _, code := range r.URL.Query()["translate"] {
+		if t, pres := translators[code]; pres {
+			t.TranslateDays(days)
+		}
+	}
+}
+
+func handleNormalize(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	normalized, fired := gorldline.DefaultNormalizer.Explain(name)
+
+	writeJson(struct {
+		Name       string                      `json:"name"`
+		Normalized string                      `json:"normalized"`
+		Rules      []gorldline.RuleApplication `json:"rules"`
+	}{name, normalized, fired}, w)
+}
+
+func handleCurrentWeekText(w http.ResponseWriter, r *http.Request) {
 	list, err := gorldline.CurrentList()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -25,17 +839,21 @@ func handleCurrentWeek(w http.ResponseWriter, _ *http.Request) {
 		return
 	}
 
-	err = nearestWeek.FetchDaysIfNeeded()
+	lang := requestLanguage(r)
+	summary, err := nearestWeek.Summary(lang)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		log.Println(err)
 		return
 	}
 
-	writeJson(nearestWeek, w)
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.Header().Set("Content-Language", lang.Code)
+	w.Header().Add("Vary", "Accept-Language")
+	_, _ = w.Write([]byte(summary))
 }
 
-func handleCurrentDay(w http.ResponseWriter, _ *http.Request) {
+func handleCurrentDayText(w http.ResponseWriter, r *http.Request) {
 	list, err := gorldline.CurrentList()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -57,10 +875,73 @@ func handleCurrentDay(w http.ResponseWriter, _ *http.Request) {
 		return
 	}
 
-	writeJson(nearestDay, w)
+	if nearestDay == nil {
+		http.Error(w, "cannot find nearest day", http.StatusNoContent)
+		return
+	}
+
+	now := time.Now()
+	lang := requestLanguage(r)
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.Header().Set("Content-Language", lang.Code)
+	w.Header().Add("Vary", "Accept-Language")
+	_, _ = w.Write([]byte(nearestDay.Summary(lang, now.After(nearestDay.Start) && now.Before(nearestDay.End))))
 }
 
-func handleCurrentDayFr(w http.ResponseWriter, _ *http.Request) {
+func requestLanguage(r *http.Request) *gorldline.Language {
+	if l := gorldline.LanguageByCode(r.URL.Query().Get("lang")); l != nil {
+		return l
+	}
+
+	return gorldline.MatchLanguage(r.Header.Get("Accept-Language"), gorldline.French)
+}
+
+func handleCurrentWeekImage(w http.ResponseWriter, r *http.Request) {
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	nearestWeek := list.Nearest()
+	if nearestWeek == nil {
+		http.Error(w, "no menu available", http.StatusBadGateway)
+		log.Println("no menu available")
+		return
+	}
+
+	opts, err := imageOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	if mux.Vars(r)["format"] == "svg" {
+		err = nearestWeek.WriteSVG(&buf, opts)
+	} else {
+		err = nearestWeek.WritePNG(&buf, opts)
+	}
+	if err == gorldline.ErrInvalidImageSize {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	if mux.Vars(r)["format"] == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+	} else {
+		w.Header().Set("Content-Type", "image/png")
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+func handleCurrentDayImage(w http.ResponseWriter, r *http.Request) {
 	list, err := gorldline.CurrentList()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -87,16 +968,184 @@ func handleCurrentDayFr(w http.ResponseWriter, _ *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
-	if nearestDay.Start.After(time.Now()) || nearestDay.End.Before(time.Now()) {
-		_, _ = w.Write([]byte("Ce menu ne correspond pas au menu d'aujourd'hui.\n"))
-		_, _ = w.Write([]byte(nearestDay.FrenchSentence(false)))
+	opts, err := imageOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	if mux.Vars(r)["format"] == "svg" {
+		err = nearestDay.WriteSVG(&buf, opts)
 	} else {
-		_, _ = w.Write([]byte(nearestDay.FrenchSentence(true)))
+		err = nearestDay.WritePNG(&buf, opts)
+	}
+	if err == gorldline.ErrInvalidImageSize {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	if mux.Vars(r)["format"] == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+	} else {
+		w.Header().Set("Content-Type", "image/png")
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+func handleEink(w http.ResponseWriter, r *http.Request) {
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	nearestWeek := list.Nearest()
+	if nearestWeek == nil {
+		http.Error(w, "no menu available", http.StatusBadGateway)
+		log.Println("no menu available")
+		return
+	}
+
+	nearestDay, err := nearestWeek.Nearest()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	if nearestDay == nil {
+		http.Error(w, "cannot find nearest day", http.StatusNoContent)
+		return
+	}
+
+	q := r.URL.Query()
+	opts := new(gorldline.EinkOptions)
+	opts.Width, _ = strconv.Atoi(q.Get("w"))
+	opts.Height, _ = strconv.Atoi(q.Get("h"))
+
+	switch q.Get("colors") {
+	case "red":
+		opts.Palette = gorldline.EinkBlackWhiteRed
+	case "yellow":
+		opts.Palette = gorldline.EinkBlackWhiteYellow
+	}
+
+	var buf bytes.Buffer
+	err = nearestDay.WriteEinkBMP(&buf, opts)
+	if err == gorldline.ErrInvalidEinkSize {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/bmp")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func handleWeekPDF(w http.ResponseWriter, r *http.Request) {
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	week := list.Find(mux.Vars(r)["id"])
+	if week == nil {
+		http.Error(w, "week not found", http.StatusNotFound)
+		return
+	}
+
+	var buf bytes.Buffer
+	err = week.WritePDF(&buf, &gorldline.PDFOptions{LogoPath: os.Getenv("PDF_LOGO")})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="menu-`+week.ID()+`.pdf"`)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func imageOptions(r *http.Request) (*gorldline.ImageOptions, error) {
+	q := r.URL.Query()
+	opts := new(gorldline.ImageOptions)
+
+	var err error
+	if v := q.Get("w"); v != "" {
+		if opts.Width, err = strconv.Atoi(v); err != nil {
+			return nil, gorldline.ErrInvalidImageSize
+		}
+	}
+	if v := q.Get("h"); v != "" {
+		if opts.Height, err = strconv.Atoi(v); err != nil {
+			return nil, gorldline.ErrInvalidImageSize
+		}
+	}
+	if v := q.Get("size"); v != "" {
+		if opts.FontSize, err = strconv.ParseFloat(v, 64); err != nil || math.IsNaN(opts.FontSize) {
+			return nil, gorldline.ErrInvalidImageSize
+		}
+	}
+
+	if q.Get("theme") == "dark" {
+		opts.Theme = gorldline.DarkImageTheme
+	}
+
+	return opts, nil
+}
+
+func handleFeed(contentType string, write func(*gorldline.Feed, io.Writer) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cached, err := feeds.get(r.URL.Query().Get("group") == "week")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			log.Println(err)
+			return
+		}
+
+		// The cached feed is shared between requests, only the copy knows its own URL.
+		feed := *cached
+
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		feed.SelfUrl = scheme + "://" + r.Host + r.URL.RequestURI()
+
+		var buf bytes.Buffer
+		err = write(&feed, &buf)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			log.Println(err)
+			return
+		}
+
+		sum := sha1.Sum(buf.Bytes())
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		http.ServeContent(w, r, "", feed.Updated, bytes.NewReader(buf.Bytes()))
 	}
 }
 
 func writeJson(element interface{}, w http.ResponseWriter) {
+	writeJsonStatus(element, http.StatusOK, w)
+}
+
+func writeJsonStatus(element interface{}, status int, w http.ResponseWriter) {
 	data, err := json.Marshal(element)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -104,15 +1153,238 @@ func writeJson(element interface{}, w http.ResponseWriter) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	_, _ = w.Write(data)
 }
 
+// Building a feed downloads and parses every week, it is kept for a while so that conditional requests stay cheap.
+type feedCache struct {
+	lock  sync.Mutex
+	feeds map[bool]*cachedFeed
+}
+
+type cachedFeed struct {
+	feed    *gorldline.Feed
+	digest  string
+	expires time.Time
+}
+
+func (c *feedCache) get(weekly bool) (*gorldline.Feed, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := time.Now()
+	previous := c.feeds[weekly]
+	if previous != nil && now.Before(previous.expires) {
+		return previous.feed, nil
+	}
+
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		return nil, err
+	}
+
+	var feed *gorldline.Feed
+	if weekly {
+		feed, err = gorldline.NewWeeksFeed(list)
+	} else {
+		feed, err = gorldline.NewDaysFeed(list)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// An unchanged menu keeps its previous date, readers and If-Modified-Since would otherwise see a new version.
+	digest := feed.Digest()
+	if previous != nil && previous.digest == digest {
+		feed.SetUpdated(previous.feed.Updated)
+	}
+
+	c.feeds[weekly] = &cachedFeed{feed, digest, now.Add(feedCacheTTL)}
+	return feed, nil
+}
+
+func loadTranslators() {
+	translators = make(map[string]*gorldline.Translator)
+	for _, l := range gorldline.Languages {
+		if l == gorldline.French {
+			continue
+		}
+
+		t := gorldline.NewTranslator(l.Code)
+		if path, set := os.LookupEnv("GLOSSARY_FILE"); set {
+			err := t.LoadGlossaryFile(path)
+			if err != nil {
+				log.Fatalln(err)
+			}
+		}
+
+		if t.Len() > 0 {
+			translators[l.Code] = t
+		}
+	}
+}
+
+func loadNormalizer() {
+	path, set := os.LookupEnv("NORMALIZATION_FILE")
+	if !set {
+		return
+	}
+
+	n, err := gorldline.LoadNormalizerFile(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	gorldline.DefaultNormalizer = n
+}
+
+func loadPriceCodes() {
+	path, set := os.LookupEnv("PRICE_CODES_FILE")
+	if !set {
+		return
+	}
+
+	err := gorldline.LoadPriceCodesFile(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func loadSubsidyRules() {
+	path, set := os.LookupEnv("SUBSIDY_FILE")
+	if !set {
+		return
+	}
+
+	rules, err := gorldline.LoadSubsidyRulesFile(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	gorldline.DefaultSubsidyRules = rules
+}
+
+func loadDietOverrides() {
+	path, set := os.LookupEnv("DIET_OVERRIDES_FILE")
+	if !set {
+		return
+	}
+
+	err := gorldline.DefaultDietClassifier.LoadOverridesFile(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func loadProfiles() {
+	path, set := os.LookupEnv("PROFILES_FILE")
+	if !set {
+		path = "profiles.json"
+	}
+
+	var err error
+	profiles, err = gorldline.NewProfileStore(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	var hosts []string
+	for _, host := range strings.Split(os.Getenv("WEBHOOK_HOSTS"), ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	webhooks = gorldline.NewWebhookNotifier(hosts, 10*time.Second)
+
+	switch os.Getenv("NOTIFIER") {
+	case "webhook":
+		notifier = webhooks
+	default:
+		notifier = &gorldline.WriterNotifier{Writer: os.Stdout}
+	}
+
+	interval, err := time.ParseDuration(os.Getenv("ALERT_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			err := sendAlerts()
+			if err != nil {
+				log.Println(err)
+			}
+		}
+	}()
+}
+
+func loadRatings() {
+	path, set := os.LookupEnv("RATINGS_FILE")
+	if !set {
+		path = "ratings.json"
+	}
+
+	var err error
+	ratings, err = gorldline.NewRatingStore(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func loadPolls() {
+	path, set := os.LookupEnv("POLLS_FILE")
+	if !set {
+		path = "polls.json"
+	}
+
+	polls = gorldline.NewPollStore(path)
+}
+
 func main() {
+	loadNormalizer()
+	loadPriceCodes()
+	loadSubsidyRules()
+	loadDietOverrides()
+	loadProfiles()
+	loadRatings()
+	loadPolls()
+	loadTranslators()
+
 	router := mux.NewRouter()
 
 	router.HandleFunc("/week/current", handleCurrentWeek)
 	router.HandleFunc("/day/current/", handleCurrentDay)
 	router.HandleFunc("/day/current/format/fr", handleCurrentDayFr)
+	router.HandleFunc("/week/current/format/text", handleCurrentWeekText)
+	router.HandleFunc("/week/current/carbon", handleCurrentWeekCarbon)
+	router.HandleFunc("/day/current/format/text", handleCurrentDayText)
+	router.HandleFunc("/week/current/format/{format:png|svg}", handleCurrentWeekImage)
+	router.HandleFunc("/day/current/format/{format:png|svg}", handleCurrentDayImage)
+	router.HandleFunc("/eink.bmp", handleEink)
+	router.HandleFunc("/meals/{id}", handleMeal)
+	router.HandleFunc("/meals/{id}/ratings", handleGetRatings).Methods(http.MethodGet)
+	router.HandleFunc("/meals/{id}/ratings", handlePostRating).Methods(http.MethodPost)
+	router.HandleFunc("/price-codes", handlePriceCodes)
+	router.HandleFunc("/search", handleSearch)
+	router.HandleFunc("/profiles/{id}", handleGetProfile).Methods(http.MethodGet)
+	router.HandleFunc("/profiles/{id}", handlePutProfile).Methods(http.MethodPut)
+	router.HandleFunc("/profiles/{id}", handleDeleteProfile).Methods(http.MethodDelete)
+	router.HandleFunc("/profiles/{id}/matches", handleProfileMatches).Methods(http.MethodGet)
+	if os.Getenv("ADMIN_TOKEN") != "" {
+		router.HandleFunc("/alerts", handleAlerts).Methods(http.MethodPost)
+	}
+	router.HandleFunc("/polls", handleCreatePoll).Methods(http.MethodPost)
+	router.HandleFunc("/polls/{id}", handleGetPoll).Methods(http.MethodGet)
+	router.HandleFunc("/polls/{id}/votes", handleVotePoll).Methods(http.MethodPost)
+	router.HandleFunc("/polls/{id}/events", handlePollEvents).Methods(http.MethodGet)
+	router.HandleFunc("/allergens", handleAllergens)
+	router.HandleFunc("/days/{date}/quote", handleQuote)
+	router.HandleFunc("/normalize", handleNormalize)
+	router.HandleFunc("/weeks/{id}.pdf", handleWeekPDF)
+	router.HandleFunc("/feed.atom", handleFeed("application/atom+xml; charset=UTF-8", (*gorldline.Feed).WriteAtom))
+	router.HandleFunc("/feed.rss", handleFeed("application/rss+xml; charset=UTF-8", (*gorldline.Feed).WriteRSS))
+	router.HandleFunc("/feed.json", handleFeed("application/feed+json; charset=UTF-8", (*gorldline.Feed).WriteJSON))
 
 	log.Println("Listening at", ":8080")
 	log.Fatalln(http.ListenAndServe(":8080", router))
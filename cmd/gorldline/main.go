@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/scotow/gorldline"
+)
+
+const (
+	usage = `usage: gorldline <command> [arguments]
+
+commands:
+    search [-history file] [-from date] [-to date] [-category name] [-min price] [-max price] [-limit n] query
+    archive [-history file]
+    plan [-week id] [-like words] [-dislike words] [-diet diet] [-without allergens] [-budget price] [-no-repeat] [-json]
+`
+)
+
+func historyPath() string {
+	if path, set := os.LookupEnv("HISTORY_FILE"); set {
+		return path
+	}
+
+	return "history.json"
+}
+
+func search(args []string) {
+	flags := flag.NewFlagSet("search", flag.ExitOnError)
+	history := flags.String("history", historyPath(), "history file")
+	from := flags.String("from", "", "first day (YYYY-MM-DD)")
+	to := flags.String("to", "", "last day (YYYY-MM-DD)")
+	category := flags.String("category", "", "category identifier or label")
+	minPrice := flags.String("min", "", "minimum price, \"3,50\" or \"350\" cents")
+	maxPrice := flags.String("max", "", "maximum price, \"3,50\" or \"350\" cents")
+	limit := flags.Int("limit", 20, "maximum number of results, 0 for all")
+	_ = flags.Parse(args)
+
+	query := &gorldline.SearchQuery{
+		Text:     strings.Join(flags.Args(), " "),
+		Category: *category,
+		Limit:    *limit,
+	}
+
+	var err error
+	if *from != "" {
+		query.From, err = gorldline.ParseDayID(*from)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if *to != "" {
+		query.To, err = gorldline.ParseDayID(*to)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if *minPrice != "" {
+		p, ok := gorldline.ParsePrice(*minPrice)
+		if !ok {
+			log.Fatalln("invalid minimum price")
+		}
+		query.MinPrice = &p
+	}
+	if *maxPrice != "" {
+		p, ok := gorldline.ParsePrice(*maxPrice)
+		if !ok {
+			log.Fatalln("invalid maximum price")
+		}
+		query.MaxPrice = &p
+	}
+
+	index := gorldline.NewSearchIndex()
+
+	days, err := gorldline.LoadHistoryFile(*history)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	index.AddDays(days)
+
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		log.Println(err)
+	} else if err = index.AddList(list); err != nil {
+		log.Println(err)
+	}
+
+	for _, m := range index.Search(query) {
+		fmt.Printf("%s\t%s\t%s\t%s\n", m.Date.Format("2006-01-02"), m.Category, m.Name, m.PriceText())
+	}
+}
+
+func archive(args []string) {
+	flags := flag.NewFlagSet("archive", flag.ExitOnError)
+	history := flags.String("history", historyPath(), "history file")
+	_ = flags.Parse(args)
+
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	days, err := list.Days()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	err = gorldline.SaveHistoryFile(*history, days)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	log.Println(len(days), "days archived to", *history)
+}
+
+func splitList(s string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func plan(args []string) {
+	flags := flag.NewFlagSet("plan", flag.ExitOnError)
+	weekID := flags.String("week", "", "week identifier, the nearest week by default")
+	likes := flags.String("like", "", "comma separated liked keywords")
+	dislikes := flags.String("dislike", "", "comma separated disliked keywords")
+	diet := flags.String("diet", "", "required diet")
+	without := flags.String("without", "", "comma separated allergens to avoid")
+	budget := flags.String("budget", "", "budget for the whole week, \"20€\" or \"2000\" cents")
+	noRepeat := flags.Bool("no-repeat", false, "never pick the same stand twice")
+	asJson := flags.Bool("json", false, "print the plan as JSON")
+	_ = flags.Parse(args)
+
+	prefs := &gorldline.PlanPreferences{
+		Likes:            splitList(*likes),
+		Dislikes:         splitList(*dislikes),
+		Diet:             *diet,
+		Without:          splitList(*without),
+		NoRepeatCategory: *noRepeat,
+	}
+
+	if prefs.Diet != "" && !gorldline.IsDiet(prefs.Diet) {
+		log.Fatalln("unknown diet:", prefs.Diet)
+	}
+	for _, id := range prefs.Without {
+		if gorldline.AllergenByID(id) == nil {
+			log.Fatalln("unknown allergen:", id)
+		}
+	}
+	if *budget != "" {
+		p, ok := gorldline.ParsePrice(*budget)
+		if !ok {
+			log.Fatalln("invalid budget")
+		}
+		prefs.Budget = &p
+	}
+
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	var week *gorldline.Week
+	if *weekID != "" {
+		week = list.Find(*weekID)
+	} else {
+		week = list.Nearest()
+	}
+	if week == nil {
+		log.Fatalln("week not found")
+	}
+
+	days, err := week.GetDays()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if path, set := os.LookupEnv("RATINGS_FILE"); set {
+		ratings, err := gorldline.NewRatingStore(path)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		ratings.Annotate(days)
+	}
+
+	result := gorldline.PlanDays(days, prefs)
+	if *asJson {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "    ")
+		err = encoder.Encode(result)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	fmt.Print(result)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "search":
+		search(os.Args[2:])
+	case "archive":
+		archive(os.Args[2:])
+	case "plan":
+		plan(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
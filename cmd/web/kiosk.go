@@ -0,0 +1,208 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/scotow/gorldline"
+)
+
+const (
+	kioskTemplate = `
+<!DOCTYPE html>
+<html lang="fr" dir="ltr">
+    <head>
+        <meta charset="utf-8">
+        <meta http-equiv="refresh" content="{{.Refresh}}">
+        <title>Worldline - Seclin - Menu</title>
+        <style media="screen">
+            html, body {
+                width: 100%;
+                height: 100%;
+                margin: 0;
+                overflow: hidden;
+                font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif;
+                background: #0066a1;
+                color: #fff;
+            }
+            header {
+                padding: 2vh 4vw;
+                font-size: 4vh;
+                opacity: 0.8;
+            }
+            section {
+                display: none;
+                padding: 0 4vw;
+            }
+            section.active {
+                display: block;
+            }
+            h1 {
+                margin: 2vh 0 4vh;
+                font-size: 9vh;
+                text-transform: uppercase;
+            }
+            ul {
+                margin: 0;
+                padding: 0;
+                list-style: none;
+            }
+            li {
+                display: flex;
+                justify-content: space-between;
+                margin-bottom: 3vh;
+                font-size: 6vh;
+            }
+            li i.carbon {
+                display: inline-block;
+                width: 3vh;
+                height: 3vh;
+                margin-right: 2vw;
+                border: 0.4vh solid #fff;
+                border-radius: 50%;
+                vertical-align: middle;
+            }
+            .carbon-low {
+                background: #2e7d32;
+            }
+            .carbon-medium {
+                background: #f9a825;
+            }
+            .carbon-high {
+                background: #c62828;
+            }
+            li em {
+                padding-left: 4vw;
+                white-space: nowrap;
+                font-style: normal;
+                opacity: 0.8;
+            }
+            section.closed h1 {
+                margin-top: 30vh;
+                text-align: center;
+            }
+            section.closed p {
+                font-size: 5vh;
+                text-align: center;
+            }
+        </style>
+    </head>
+    <body>
+        <header>{{weekday .Date}} {{date .Date}}</header>
+        {{if .Day}}
+        {{$day := .Day}}
+        {{range .Day.OrderedCategories}}
+        <section>
+            <h1>{{.Label}}</h1>
+            <ul>
+                {{range index $day.Meals .Label}}
+                <li><span>{{with .Carbon}}<i class="carbon carbon-{{.Level}}" title="{{.}}"></i>{{end}}{{.Name}}</span>{{if .PriceText}}<em{{with .PriceCode}} title="{{.}}"{{end}}>{{.PriceText}}</em>{{end}}</li>
+                {{end}}
+            </ul>
+        </section>
+        {{end}}
+        {{else}}
+        <section class="closed active">
+            <h1>Fermé</h1>
+            <p>Pas de service ce jour.</p>
+        </section>
+        {{end}}
+        <script>
+            var slides = document.querySelectorAll("section");
+            var current = 0;
+            function show() {
+                for (var i = 0; i < slides.length; i++) {
+                    slides[i].classList.toggle("active", i === current);
+                }
+                current = (current + 1) % slides.length;
+            }
+            if (slides.length > 0) {
+                show();
+                setInterval(show, {{.Cycle}});
+            }
+        </script>
+    </body>
+</html>
+`
+)
+
+const (
+	defaultKioskCutoff  = "14:00"
+	defaultKioskRefresh = 5 * time.Minute
+	defaultKioskCycle   = 8 * time.Second
+)
+
+var (
+	kioskPage = template.Must(template.New("kiosk").Funcs(template.FuncMap{
+		"date":    formatDate,
+		"weekday": formatWeekday,
+	}).Parse(kioskTemplate))
+	kioskLocation *time.Location
+)
+
+func init() {
+	l, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		l = time.Local
+	}
+
+	kioskLocation = l
+}
+
+type kiosk struct {
+	Date    time.Time
+	Day     *gorldline.Day
+	Refresh int
+	Cycle   int64
+}
+
+func handleKiosk(w http.ResponseWriter, _ *http.Request) {
+	date := kioskDate(time.Now().In(kioskLocation))
+	k := kiosk{
+		Date:    date,
+		Refresh: int(envDuration("KIOSK_REFRESH", defaultKioskRefresh).Seconds()),
+		Cycle:   envDuration("KIOSK_CYCLE", defaultKioskCycle).Milliseconds(),
+	}
+
+	if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+		list, err := gorldline.CurrentList()
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+
+		k.Day, err = list.Day(date)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	_ = kioskPage.Execute(w, k)
+}
+
+func kioskDate(now time.Time) time.Time {
+	cutoff, err := time.Parse("15:04", os.Getenv("KIOSK_CUTOFF"))
+	if err != nil {
+		cutoff, _ = time.Parse("15:04", defaultKioskCutoff)
+	}
+
+	date := time.Date(now.Year(), now.Month(), now.Day(), 12, 0, 0, 0, now.Location())
+	if now.Hour()*60+now.Minute() >= cutoff.Hour()*60+cutoff.Minute() {
+		date = date.AddDate(0, 0, 1)
+	}
+
+	return date
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+
+	return d
+}
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"html/template"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/scotow/gorldline"
 )
@@ -12,43 +14,192 @@ import (
 const (
 	htmlTemplate = `
 <!DOCTYPE html>
-<html lang="en" dir="ltr">
+<html lang="fr" dir="ltr">
     <head>
         <meta charset="utf-8">
+        <meta name="viewport" content="width=device-width, initial-scale=1">
         <title>Worldline - Seclin - Menu</title>
         <style media="screen">
-            html, body, iframe {
-				position: absolute;
-				top: 0;
-				left: 0;
-                width: 100%;
-                height: 100%;
+            * {
+                box-sizing: border-box;
+            }
+            body {
                 margin: 0;
-                border: none;
-                font-size: 0;
-            }
-			div {
-				margin-top: 20px;
-				font-size: 32px;
-				text-align: center;
-				font-family: monospace;
-			}
+                padding: 16px;
+                font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif;
+                background: #f4f4f4;
+                color: #222;
+            }
+            header {
+                display: flex;
+                align-items: center;
+                justify-content: space-between;
+                max-width: 1200px;
+                margin: 0 auto 16px;
+            }
+            header h1 {
+                margin: 0;
+                font-size: 1.3em;
+                text-align: center;
+            }
+            header a, header span {
+                min-width: 3em;
+                font-size: 1.5em;
+                color: #0066a1;
+                text-decoration: none;
+                text-align: center;
+            }
+            main {
+                display: grid;
+                grid-template-columns: repeat(auto-fit, minmax(220px, 1fr));
+                gap: 16px;
+                max-width: 1200px;
+                margin: 0 auto;
+            }
+            article {
+                padding: 12px 16px;
+                background: #fff;
+                border-radius: 6px;
+                border-top: 4px solid #ccc;
+            }
+            article.today {
+                border-top-color: #0066a1;
+                box-shadow: 0 0 0 2px #0066a1;
+            }
+            article h2 {
+                margin: 0 0 8px;
+                font-size: 1.1em;
+            }
+            article h3 {
+                margin: 12px 0 4px;
+                font-size: 0.8em;
+                text-transform: uppercase;
+                color: #666;
+            }
+            ul {
+                margin: 0;
+                padding: 0;
+                list-style: none;
+            }
+            li {
+                display: flex;
+                justify-content: space-between;
+                padding: 2px 0;
+            }
+            li em {
+                padding-left: 8px;
+                white-space: nowrap;
+                color: #666;
+            }
+            li small {
+                display: block;
+                font-size: 0.75em;
+                color: #888;
+            }
+            li i.rating {
+                margin-left: 6px;
+                font-size: 0.75em;
+                font-style: normal;
+                color: #b8860b;
+            }
+            li b.carbon {
+                display: inline-block;
+                margin-top: 2px;
+                padding: 0 6px;
+                border-radius: 8px;
+                font-size: 0.7em;
+                font-weight: normal;
+                color: #fff;
+            }
+            .carbon-low {
+                background: #2e7d32;
+            }
+            .carbon-medium {
+                background: #f9a825;
+            }
+            .carbon-high {
+                background: #c62828;
+            }
+            footer {
+                margin-top: 16px;
+                text-align: center;
+                font-size: 0.8em;
+            }
+            footer a {
+                color: #666;
+            }
         </style>
     </head>
     <body>
-		<div>Loading...</div>
-        <iframe src="https://sheet.zoho.com/sheet/view.do?url={{.Link}}&name=menu">Your browser doesn't support iFrames.</iframe>
+        <header>
+            {{if .Previous}}<a href="/?week={{.Previous.ID}}" title="Semaine précédente">&larr;</a>{{else}}<span></span>{{end}}
+            <h1>Menu du {{date .Week.Start}} au {{date .Week.End}}</h1>
+            {{if .Next}}<a href="/?week={{.Next.ID}}" title="Semaine suivante">&rarr;</a>{{else}}<span></span>{{end}}
+        </header>
+        <main>
+            {{range .Week.Days}}
+            <article{{if today .}} class="today"{{end}}>
+                <h2>{{weekday .Start}} {{date .Start}}</h2>
+                {{$day := .}}
+                {{range .OrderedCategories}}
+                <h3>{{.Label}}</h3>
+                <ul>
+                    {{range index $day.Meals .Label}}
+                    <li><span>{{.Name}}{{with .Nutrition}}<small title="Protéines {{.Protein}} g, lipides {{.Fat}} g, glucides {{.Carbs}} g">≈ {{.Energy}} kcal</small>{{end}}{{with .Carbon}}<b class="carbon carbon-{{.Level}}" title="{{.}}">{{.Badge}}</b>{{end}}{{with .Rating}}<i class="rating">{{.}}</i>{{end}}</span>{{if .PriceText}}<em{{with .PriceCode}} title="{{.}}"{{end}}>{{.PriceText}}</em>{{end}}</li>
+                    {{end}}
+                </ul>
+                {{end}}
+            </article>
+            {{end}}
+        </main>
+        <footer>
+            <a href="/download?week={{.Week.ID}}">Télécharger le fichier original</a>
+        </footer>
     </body>
 </html>
 `
 )
 
 var (
-	mainPage = template.Must(template.New("main").Parse(htmlTemplate))
+	weekdays = [...]string{
+		"Dimanche",
+		"Lundi",
+		"Mardi",
+		"Mercredi",
+		"Jeudi",
+		"Vendredi",
+		"Samedi",
+	}
+	months = [...]string{
+		"janvier",
+		"février",
+		"mars",
+		"avril",
+		"mai",
+		"juin",
+		"juillet",
+		"août",
+		"septembre",
+		"octobre",
+		"novembre",
+		"décembre",
+	}
+	mainPage = template.Must(template.New("main").Funcs(template.FuncMap{
+		"date":    formatDate,
+		"weekday": formatWeekday,
+		"today":   isToday,
+	}).Parse(htmlTemplate))
+	ratings *gorldline.RatingStore
 )
 
+type page struct {
+	Week     *gorldline.Week
+	Previous *gorldline.Week
+	Next     *gorldline.Week
+}
+
 func handle(w http.ResponseWriter, r *http.Request) {
-	if r.RequestURI == "/list" {
+	if r.URL.Path == "/list" {
 		redirectToList(w, r)
 		return
 	}
@@ -59,24 +210,82 @@ func handle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	nearest := list.Nearest()
-	if nearest == nil {
+	var week *gorldline.Week
+	if id := r.URL.Query().Get("week"); id != "" {
+		week = list.Find(id)
+	} else {
+		week = list.Nearest()
+	}
+
+	if week == nil {
 		redirectToList(w, r)
 		return
 	}
 
-	if r.RequestURI == "/direct" || r.RequestURI == "/download" {
-		http.Redirect(w, r, nearest.LinkOrPath, http.StatusTemporaryRedirect)
-	} else {
-		w.Header().Set("Content-Type", "text/html")
-		_ = mainPage.Execute(w, nearest)
+	if r.URL.Path == "/direct" || r.URL.Path == "/download" {
+		http.Redirect(w, r, week.LinkOrPath, http.StatusTemporaryRedirect)
+		return
+	}
+
+	err = week.FetchDaysIfNeeded()
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	if _, set := os.LookupEnv("NUTRITION"); set {
+		gorldline.DefaultNutritionEstimator.EstimateDays(week.Days)
+	}
+
+	ratings.Annotate(week.Days)
+
+	p := page{Week: week}
+	for i, other := range list.Weeks {
+		if other == week {
+			if i > 0 {
+				p.Previous = list.Weeks[i-1]
+			}
+			if i < len(list.Weeks)-1 {
+				p.Next = list.Weeks[i+1]
+			}
+			break
+		}
 	}
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	_ = mainPage.Execute(w, p)
 }
 
 func redirectToList(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, gorldline.DefaultBaseUrl+gorldline.MenusUri, http.StatusTemporaryRedirect)
 }
 
+func formatDate(t time.Time) string {
+	return fmt.Sprintf("%d %s", t.Day(), months[t.Month()-1])
+}
+
+func formatWeekday(t time.Time) string {
+	return weekdays[t.Weekday()]
+}
+
+func isToday(d *gorldline.Day) bool {
+	now := time.Now()
+	return now.After(d.Start) && now.Before(d.End)
+}
+
+func loadRatings() {
+	path, set := os.LookupEnv("RATINGS_FILE")
+	if !set {
+		path = "ratings.json"
+	}
+
+	var err error
+	ratings, err = gorldline.NewRatingStore(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
 func listeningAddress() string {
 	port, set := os.LookupEnv("PORT")
 	if !set {
@@ -87,6 +296,20 @@ func listeningAddress() string {
 }
 
 func main() {
+	if path, set := os.LookupEnv("PRICE_CODES_FILE"); set {
+		err := gorldline.LoadPriceCodesFile(path)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	loadRatings()
+	loadPolls()
+
 	http.HandleFunc("/", handle)
+	http.HandleFunc("/kiosk", handleKiosk)
+	http.HandleFunc("/poll", handlePoll)
+	http.HandleFunc("/poll/vote", handlePollVote)
+	http.HandleFunc("/poll/results", handlePollResults)
 	log.Fatal(http.ListenAndServe(listeningAddress(), nil))
 }
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/scotow/gorldline"
+)
+
+const (
+	pollTemplate = `
+<!DOCTYPE html>
+<html lang="fr" dir="ltr">
+    <head>
+        <meta charset="utf-8">
+        <meta name="viewport" content="width=device-width, initial-scale=1">
+        <title>Worldline - Seclin - Sondage</title>
+        <style media="screen">
+            * {
+                box-sizing: border-box;
+            }
+            body {
+                max-width: 640px;
+                margin: 0 auto;
+                padding: 16px;
+                font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif;
+                background: #f4f4f4;
+                color: #222;
+            }
+            h1 {
+                margin: 0 0 4px;
+                font-size: 24px;
+            }
+            h2 {
+                margin: 24px 0 8px;
+                font-size: 18px;
+            }
+            header p, .hint {
+                margin: 0;
+                color: #666;
+            }
+            form, .results {
+                margin-top: 16px;
+                padding: 16px;
+                border-radius: 8px;
+                background: #fff;
+            }
+            label {
+                display: block;
+                margin-bottom: 8px;
+            }
+            label small {
+                display: block;
+                margin-left: 24px;
+                color: #666;
+            }
+            input[type=text], select {
+                padding: 6px;
+                font-size: 16px;
+            }
+            button {
+                margin-top: 8px;
+                padding: 8px 16px;
+                border: none;
+                border-radius: 4px;
+                background: #0066a1;
+                color: #fff;
+                font-size: 16px;
+            }
+            .bar {
+                height: 8px;
+                margin: 2px 0 12px;
+                border-radius: 4px;
+                background: #0066a1;
+            }
+            .voters {
+                font-size: 14px;
+                color: #666;
+            }
+            .error {
+                color: #c62828;
+            }
+        </style>
+    </head>
+    <body>
+        {{if .Poll}}
+        {{$poll := .Poll}}
+        <header>
+            <h1>{{if $poll.Title}}{{$poll.Title}}{{else}}On mange où ?{{end}}</h1>
+            <p>{{weekday $poll.Date}} {{date $poll.Date}}, fin du vote à {{clock $poll.Closes}}</p>
+        </header>
+        {{with .Error}}<p class="error">{{.}}</p>{{end}}
+        {{if not .Closed}}
+        <form method="post" action="/poll/vote">
+            <input type="hidden" name="id" value="{{$poll.ID}}">
+            <h2>Votre nom</h2>
+            <input type="text" name="name" value="{{with .Vote}}{{.Name}}{{end}}">
+            <h2>Stand</h2>
+            {{range $poll.Options}}
+            <label><input type="radio" name="option" value="{{.ID}}" required{{if $.Vote}}{{if eq $.Vote.Option .ID}} checked{{end}}{{end}}> {{.Label}}<small>{{join .Meals}}</small></label>
+            {{end}}
+            <h2>Départ</h2>
+            <select name="time">
+                {{range $poll.Times}}
+                <option{{if $.Vote}}{{if eq $.Vote.Time .}} selected{{end}}{{end}}>{{.}}</option>
+                {{end}}
+            </select>
+            <div><button type="submit">{{if .Vote}}Modifier mon vote{{else}}Voter{{end}}</button></div>
+        </form>
+        {{end}}
+        <div class="results">
+            <h2>Stands</h2>
+            <div id="options"></div>
+            <h2>Départs</h2>
+            <div id="times"></div>
+            <p class="hint" id="status"></p>
+        </div>
+        <script>
+            function render(id, results, label) {
+                var container = document.getElementById(id);
+                container.innerHTML = "";
+                var max = 1;
+                results.forEach(function (r) { max = Math.max(max, r.votes); });
+                results.forEach(function (r) {
+                    var row = document.createElement("div");
+                    row.textContent = label(r) + " : " + r.votes;
+                    var bar = document.createElement("div");
+                    bar.className = "bar";
+                    bar.style.width = (r.votes / max * 100) + "%";
+                    var voters = document.createElement("div");
+                    voters.className = "voters";
+                    voters.textContent = r.voters.join(", ");
+                    container.append(row, voters, bar);
+                });
+            }
+            function refresh() {
+                fetch("/poll/results?id={{$poll.ID}}").then(function (res) {
+                    return res.json();
+                }).then(function (results) {
+                    render("options", results.options, function (r) { return r.label; });
+                    render("times", results.times, function (r) { return r.time; });
+                    document.getElementById("status").textContent = results.closed ? "Vote terminé." : results.total + " participant(s), mise à jour automatique.";
+                    if (!results.closed) {
+                        setTimeout(refresh, {{.Refresh}});
+                    }
+                });
+            }
+            refresh();
+        </script>
+        {{else}}
+        <header>
+            <h1>Nouveau sondage</h1>
+            <p>{{weekday .Date}} {{date .Date}}</p>
+        </header>
+        {{with .Error}}<p class="error">{{.}}</p>{{end}}
+        <form method="post" action="/poll">
+            <h2>Titre</h2>
+            <input type="text" name="title" placeholder="On mange où ?">
+            <h2>Fin du vote</h2>
+            <input type="text" name="closes" value="{{.Closes}}" pattern="[0-9]{2}:[0-9]{2}">
+            <div><button type="submit">Créer</button></div>
+        </form>
+        {{end}}
+    </body>
+</html>
+`
+)
+
+const (
+	defaultPollClosing = "11:30"
+	pollUserCookie     = "gorldline_user"
+	pollRefresh        = 5 * time.Second
+)
+
+var (
+	pollPage = template.Must(template.New("poll").Funcs(template.FuncMap{
+		"date":    formatDate,
+		"weekday": formatWeekday,
+		"clock":   formatClock,
+		"join":    gorldline.French.Join,
+	}).Parse(pollTemplate))
+	polls *gorldline.PollStore
+)
+
+type pollView struct {
+	Date    time.Time
+	Closes  string
+	Poll    *gorldline.Poll
+	Vote    *gorldline.PollVote
+	Closed  bool
+	Error   string
+	Refresh int64
+}
+
+func loadPolls() {
+	path, set := os.LookupEnv("POLLS_FILE")
+	if !set {
+		path = "polls.json"
+	}
+
+	polls = gorldline.NewPollStore(path)
+}
+
+func handlePoll(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		createPoll(w, r)
+		return
+	}
+
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		renderPoll(w, pollView{Date: time.Now().In(kioskLocation), Closes: defaultPollClosing})
+		return
+	}
+
+	poll, err := polls.Get(id)
+	if err == gorldline.ErrPollNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	v := pollView{Poll: poll, Closed: poll.Closed(time.Now())}
+	if c, err := r.Cookie(pollUserCookie); err == nil {
+		v.Vote = poll.VoteOf(c.Value)
+	}
+	renderPoll(w, v)
+}
+
+func createPoll(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().In(kioskLocation)
+	v := pollView{Date: now, Closes: r.FormValue("closes")}
+
+	list, err := gorldline.CurrentList()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	day, err := list.Day(now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+	if day == nil {
+		v.Error = "Pas de service aujourd'hui."
+		renderPoll(w, v)
+		return
+	}
+
+	closes, err := gorldline.AtClock(day.Start, v.Closes)
+	if err != nil {
+		v.Error = "Heure de fin invalide."
+		renderPoll(w, v)
+		return
+	}
+	if !closes.After(now) {
+		v.Error = "L'heure de fin est déjà passée."
+		renderPoll(w, v)
+		return
+	}
+
+	poll, err := gorldline.NewPoll(day, r.FormValue("title"), nil, closes)
+	if err == gorldline.ErrEmptyPoll {
+		v.Error = "Aucun stand ouvert aujourd'hui."
+		renderPoll(w, v)
+		return
+	}
+	if err == nil {
+		err = polls.Create(poll)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	http.Redirect(w, r, "/poll?id="+poll.ID, http.StatusSeeOther)
+}
+
+func handlePollVote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, err := pollUserToken(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	id := r.FormValue("id")
+	_, err = polls.Vote(id, token, r.FormValue("name"), r.FormValue("option"), r.FormValue("time"), time.Now())
+	switch err {
+	case nil:
+		http.Redirect(w, r, "/poll?id="+id, http.StatusSeeOther)
+	case gorldline.ErrPollNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case gorldline.ErrPollClosed, gorldline.ErrInvalidPollOption, gorldline.ErrInvalidDepartureTime:
+		poll, getErr := polls.Get(id)
+		if getErr != nil {
+			http.Error(w, getErr.Error(), http.StatusNotFound)
+			return
+		}
+		renderPoll(w, pollView{Poll: poll, Closed: poll.Closed(time.Now()), Error: pollErrors[err]})
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+	}
+}
+
+var pollErrors = map[error]string{
+	gorldline.ErrPollClosed:           "Le vote est terminé.",
+	gorldline.ErrInvalidPollOption:    "Choisissez un stand.",
+	gorldline.ErrInvalidDepartureTime: "Choisissez une heure de départ.",
+}
+
+func handlePollResults(w http.ResponseWriter, r *http.Request) {
+	poll, err := polls.Get(r.URL.Query().Get("id"))
+	if err == gorldline.ErrPollNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		log.Println(err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(poll.Results(time.Now()))
+}
+
+func renderPoll(w http.ResponseWriter, v pollView) {
+	v.Refresh = pollRefresh.Milliseconds()
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	if v.Error != "" {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	_ = pollPage.Execute(w, v)
+}
+
+// Visitors are identified by a random cookie, so that voting again replaces the previous vote.
+func pollUserToken(w http.ResponseWriter, r *http.Request) (string, error) {
+	if c, err := r.Cookie(pollUserCookie); err == nil && c.Value != "" {
+		return c.Value, nil
+	}
+
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	token := hex.EncodeToString(b)
+	http.SetCookie(w, &http.Cookie{
+		Name:     pollUserCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  time.Now().AddDate(1, 0, 0),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return token, nil
+}
+
+func formatClock(t time.Time) string {
+	return t.In(kioskLocation).Format("15:04")
+}
@@ -0,0 +1,207 @@
+package gorldline
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	sideSeparatorRegex = regexp.MustCompile(`(?i)\s*(?:[,;/+]|\s(?:avec|accompagnée?s? de|et (?:ses|sa|son)|servie?s? avec)\s)\s*`)
+	sauceRegex         = regexp.MustCompile(`(?i)\s(?:(?:au|aux|à la|a la)\s)?(?:sauce|jus|coulis|fondue)(?:\s.+)?$`)
+
+	// Sauces and preparations introduced by "à la", "au" or "aux", or used alone as a suffix.
+	sauceLexicon = []string{
+		"à la crème", "à la moutarde", "à la provençale", "à la normande", "à la forestière", "à la tomate",
+		"à la basquaise", "à la bordelaise", "au curry", "au poivre", "au beurre blanc", "au beurre", "au roquefort",
+		"au pistou", "au vin blanc", "au vin rouge", "aux champignons", "aux herbes", "aux fines herbes",
+		"basquaise", "bolognaise", "carbonara", "provençale", "forestière", "normande", "béarnaise", "hollandaise",
+		"meunière", "marinière", "dijonnaise", "bordelaise", "tandoori", "teriyaki", "aigre-douce",
+	}
+
+	sauceConnectors = []string{"a la", "aux", "au"}
+
+	// Canonical cooking method for each inflected form.
+	methodLexicon = map[string]string{
+		"roti": "rôti", "rotie": "rôti", "grille": "grillé", "grillee": "grillé", "pane": "pané", "panee": "pané",
+		"braise": "braisé", "braisee": "braisé", "saute": "sauté", "sautee": "sauté", "poele": "poêlé",
+		"poelee": "poêlé", "mijote": "mijoté", "mijotee": "mijoté", "frit": "frit",
+		"gratine": "gratiné", "gratinee": "gratiné", "fume": "fumé", "fumee": "fumé", "confit": "confit",
+		"confite": "confit", "poche": "poché", "pochee": "poché", "vapeur": "vapeur", "au four": "au four",
+		"en papillote": "en papillote", "a la plancha": "à la plancha", "marine": "mariné", "marinee": "mariné",
+		"caramelise": "caramélisé", "caramelisee": "caramélisé", "farci": "farci", "farcie": "farci",
+		"roule": "roulé", "roulee": "roulé", "laque": "laqué", "laquee": "laqué",
+	}
+
+	// Words that describe a cut, a form or a quality rather than an ingredient.
+	ingredientQualifiers = []string{
+		"sans", "jour", "sauce", "jus", "coulis", "fondue", "accompagne",
+		"accompagnee", "servi", "servie", "filet", "dos", "pave", "cuisse", "escalope", "supreme", "tranche",
+		"morceau", "aiguillette", "emince", "brochette", "mini", "blanc", "blanche", "rouge", "vert", "verte",
+		"noir", "noire", "frais", "fraiche", "doux", "douce", "fort", "forte", "petit", "petite", "grand",
+		"grande", "nouveau", "nouvelle", "fin", "fine", "pilaf", "saison", "ancienne",
+	}
+)
+
+type Components struct {
+	Main        string   `json:"main"`
+	Method      string   `json:"method,omitempty"`
+	Sauce       string   `json:"sauce,omitempty"`
+	Sides       []string `json:"sides,omitempty"`
+	Ingredients []string `json:"ingredients"`
+}
+
+func ParseComponents(name string) *Components {
+	c := new(Components)
+
+	parts := sideSeparatorRegex.Split(strings.TrimSpace(name), -1)
+	main := parts[0]
+	for _, side := range parts[1:] {
+		if side = strings.TrimSpace(side); side != "" {
+			c.Sides = append(c.Sides, side)
+		}
+	}
+
+	if loc := sauceRegex.FindStringIndex(main); loc != nil {
+		c.Sauce = strings.TrimSpace(main[loc[0]:])
+		main = main[:loc[0]]
+	} else {
+		main, c.Sauce = extractSauce(main)
+	}
+
+	main, c.Method = extractMethod(main)
+	c.Main = strings.TrimSpace(main)
+	c.Ingredients = lemmatizeIngredients(name)
+
+	return c
+}
+
+// Each component is matched on its own, so that a term never spans the dish, its sauce and its sides.
+func (m *Meal) componentTexts() []string {
+	if m.Components == nil {
+		return []string{m.Name}
+	}
+
+	texts := make([]string, 0, 3+len(m.Components.Sides))
+	for _, t := range append([]string{m.Components.Main, m.Components.Method, m.Components.Sauce}, m.Components.Sides...) {
+		if t != "" {
+			texts = append(texts, t)
+		}
+	}
+
+	return texts
+}
+
+func extractSauce(s string) (string, string) {
+	words := wordRegex.FindAllStringIndex(s, -1)
+	for _, sauce := range sauceLexicon {
+		n := len(wordRegex.FindAllString(sauce, -1))
+		if n >= len(words) {
+			continue
+		}
+
+		key, ok := phraseKey(s, words[len(words)-n:])
+		if ok && key == glossaryKey(sauce) {
+			start := words[len(words)-n][0]
+			// "Lasagnes à la bolognaise" keeps its connector with the sauce.
+			for k := 2; k > 0; k-- {
+				first := len(words) - n - k
+				if first < 1 {
+					continue
+				}
+				if key, ok := phraseKey(s, words[first:len(words)-n]); ok && containsString(sauceConnectors, key) {
+					start = words[first][0]
+					break
+				}
+			}
+			return s[:start], strings.TrimSpace(s[start:])
+		}
+	}
+
+	return s, ""
+}
+
+func extractMethod(s string) (string, string) {
+	words := wordRegex.FindAllStringIndex(s, -1)
+	for n := 3; n > 0; n-- {
+		for i := 0; i+n <= len(words); i++ {
+			key, ok := phraseKey(s, words[i:i+n])
+			if !ok {
+				continue
+			}
+
+			method, pres := methodLexicon[strings.ReplaceAll(key, "'", " ")]
+			if !pres {
+				continue
+			}
+
+			// The first word is always the dish itself, "Rôti de porc" is not a roasting method.
+			if i == 0 {
+				continue
+			}
+
+			start, end := words[i][0], words[i+n-1][1]
+			return strings.Join(strings.Fields(s[:start]+s[end:]), " "), method
+		}
+	}
+
+	return s, ""
+}
+
+func lemmatizeIngredients(s string) []string {
+	ingredients := make([]string, 0)
+	for _, w := range wordRegex.FindAllString(s, -1) {
+		lemma := Lemmatize(w)
+		key := foldAccents(lemma)
+		if len(key) < 2 || isStopWord(w) || containsString(ingredientQualifiers, key) {
+			continue
+		}
+		if _, pres := methodLexicon[key]; pres {
+			continue
+		}
+		if !containsString(ingredients, lemma) {
+			ingredients = append(ingredients, lemma)
+		}
+	}
+
+	return ingredients
+}
+
+// The lemma keeps its accents so that it can be displayed, "Carottes" becomes "carotte".
+func Lemmatize(word string) string {
+	return singularFrench(strings.ToLower(word))
+}
+
+func (m *Meal) HasIngredient(ingredient string) bool {
+	if m.Components == nil {
+		return false
+	}
+
+	key := foldAccents(Lemmatize(strings.TrimSpace(ingredient)))
+	for _, i := range m.Components.Ingredients {
+		if foldAccents(i) == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *Week) MealsWithIngredient(ingredient string) ([]*Meal, error) {
+	days, err := w.GetDays()
+	if err != nil {
+		return nil, err
+	}
+
+	meals := make([]*Meal, 0)
+	for _, d := range days {
+		for _, c := range d.OrderedCategories() {
+			for _, m := range d.Meals[c.Label] {
+				if m.HasIngredient(ingredient) {
+					meals = append(meals, m)
+				}
+			}
+		}
+	}
+
+	return meals, nil
+}
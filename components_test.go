@@ -0,0 +1,57 @@
+package gorldline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseComponents(t *testing.T) {
+	cases := []struct {
+		name string
+		want Components
+	}{
+		{"Poulet rôti, frites", Components{
+			Main: "Poulet", Method: "rôti", Sides: []string{"frites"}, Ingredients: []string{"poulet", "frite"},
+		}},
+		{"Filet de colin sauce beurre blanc avec riz pilaf", Components{
+			Main: "Filet de colin", Sauce: "sauce beurre blanc", Sides: []string{"riz pilaf"}, Ingredients: []string{"colin", "beurre", "riz"},
+		}},
+		{"Escalope de dinde à la crème et ses haricots verts", Components{
+			Main: "Escalope de dinde", Sauce: "à la crème", Sides: []string{"haricots verts"}, Ingredients: []string{"dinde", "crème", "haricot"},
+		}},
+		{"Saumon grillé au beurre / légumes de saison", Components{
+			Main: "Saumon", Method: "grillé", Sauce: "au beurre", Sides: []string{"légumes de saison"}, Ingredients: []string{"saumon", "beurre", "légume"},
+		}},
+		{"Lasagnes bolognaise", Components{
+			Main: "Lasagnes", Sauce: "bolognaise", Ingredients: []string{"lasagne", "bolognaise"},
+		}},
+		{"Tarte aux pommes", Components{
+			Main: "Tarte aux pommes", Ingredients: []string{"tarte", "pomme"},
+		}},
+	}
+
+	for _, c := range cases {
+		if got := ParseComponents(c.name); !reflect.DeepEqual(*got, c.want) {
+			t.Errorf("ParseComponents(%q) = %+v, want %+v", c.name, *got, c.want)
+		}
+	}
+}
+
+func TestHasIngredient(t *testing.T) {
+	m := &Meal{Name: "Escalope de dinde à la crème et ses haricots verts"}
+	m.Components = ParseComponents(m.Name)
+
+	cases := map[string]bool{
+		"dinde":     true,
+		"Haricots":  true,
+		"creme":     true,
+		"poulet":    false,
+		"escalope":  false,
+		" haricot ": true,
+	}
+	for ingredient, want := range cases {
+		if got := m.HasIngredient(ingredient); got != want {
+			t.Errorf("HasIngredient(%q) = %v, want %v", ingredient, got, want)
+		}
+	}
+}
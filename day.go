@@ -3,16 +3,31 @@ package gorldline
 import (
 	"errors"
 	"fmt"
+	"html/template"
 	"io"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/olekukonko/tablewriter"
 )
 
+const (
+	dayIdFormat = "2006-01-02"
+)
+
 var (
 	ErrInvalidDayData = errors.New("invalid day data")
+	ErrInvalidDayID   = errors.New("invalid day identifier")
+)
+
+var (
+	dayHtmlTemplate = template.Must(template.New("day").Parse(`{{range .}}<h3>{{.Type}}</h3>
+<ul>
+{{- range .Meals}}
+	<li>{{.Name}}{{with .PriceText}} <em>{{.}}</em>{{end}}{{with .Nutrition}} <small>≈ {{.Energy}} kcal</small>{{end}}{{with .Carbon}} <span class="carbon carbon-{{.Level}}" title="{{.}}">{{.Badge}}</span>{{end}}{{with .Rating}} <span class="rating">{{.}}</span>{{end}}</li>
+{{- end}}
+</ul>
+{{end}}`))
 )
 
 func NewDayRaw(types, names, prices []string, start, end time.Time) (*Day, error) {
@@ -25,7 +40,14 @@ func NewDayRaw(types, names, prices []string, start, end time.Time) (*Day, error
 	for i, t := range types {
 		m := new(Meal)
 		m.Name = smoothGrammar(names[i])
-		m.Price = parsePrice(prices[i])
+		m.Price, m.Priced = ParsePrice(prices[i])
+		m.RawPrice = strings.TrimSpace(prices[i])
+		if !m.Priced {
+			m.PriceCode = parsePriceCode(prices[i])
+			if m.PriceCode != nil && m.PriceCode.Amount != nil {
+				m.Price, m.Priced = *m.PriceCode.Amount, true
+			}
+		}
 
 		if others, pres := meals[t]; pres {
 			meals[t] = append(others, m)
@@ -36,8 +58,11 @@ func NewDayRaw(types, names, prices []string, start, end time.Time) (*Day, error
 
 	d := new(Day)
 	d.Meals = meals
+	d.Categories = categorize(meals)
 	d.Start = start
 	d.End = end
+	d.identifyMeals()
+	d.tagMeals()
 
 	return d, nil
 }
@@ -45,58 +70,25 @@ func NewDayRaw(types, names, prices []string, start, end time.Time) (*Day, error
 func NewDay(meals map[string][]*Meal, start, end time.Time) *Day {
 	d := new(Day)
 	d.Meals = meals
+	d.Categories = categorize(meals)
 	d.Start = start
 	d.End = end
+	d.identifyMeals()
+	d.tagMeals()
 
 	return d
 }
 
 type Day struct {
-	Meals map[string][]*Meal `json:"meals"`
-	Start time.Time          `json:"start"`
-	End   time.Time          `json:"end"`
+	Meals      map[string][]*Meal `json:"meals"`
+	Categories []*DayCategory     `json:"categories"`
+	Start      time.Time          `json:"start"`
+	End        time.Time          `json:"end"`
 }
 
+// Deprecated: use Summary with French instead.
 func (d *Day) FrenchSentence(today bool) string {
-	var b strings.Builder
-
-	if today {
-		b.WriteString("Aujourd'hui, ")
-	} else {
-		b.WriteString("Ce jour, ")
-	}
-
-	if v, p := d.Meals["Plat du Jour"]; p && len(v) > 0 {
-		b.WriteString(v[0].Name)
-		b.WriteString(" sera le plat du jour. ")
-	}
-
-	if v, p := d.Meals["Trattoria"]; p && len(v) > 0 {
-		b.WriteString("Le stand Trattoria vous propose ")
-		b.WriteString(v[0].Name)
-		b.WriteString(". ")
-	}
-
-	if v, p := d.Meals["Cuisine du Monde"]; p && len(v) > 0 {
-		b.WriteString("Le plat de la cuisine du monde sera ")
-		b.WriteString(v[0].Name)
-		b.WriteString(". ")
-	}
-
-	if v, p := d.Meals["Bar a Legumes"]; p && len(v) > 0 {
-		acc := make([]string, 0, len(d.Meals["Bar a Legumes"]))
-		for _, a := range v {
-			acc = append(acc, a.Name)
-		}
-
-		b.WriteString("Les accompagnements seront ")
-		b.WriteString(strings.Join(acc[:cap(acc)-2], ", "))
-		b.WriteString(" et ")
-		b.WriteString(acc[len(acc)-1])
-		b.WriteString(". ")
-	}
-
-	return strings.TrimSpace(b.String())
+	return d.Summary(French, today)
 }
 
 func (d *Day) WriteAsciiTable(w io.Writer) error {
@@ -111,7 +103,8 @@ func (d *Day) WriteAsciiTable(w io.Writer) error {
 	}
 
 	col := 0
-	for k, v := range d.Meals {
+	for _, k := range d.sortedTypes() {
+		v := d.Meals[k]
 		data[0][col] = strings.ToUpper(k)
 		data[0][col + 1] = ""
 		for i, m := range v {
@@ -119,9 +112,7 @@ func (d *Day) WriteAsciiTable(w io.Writer) error {
 				data = append(data, make([]string, len(data[0])))
 			}
 			data[i + 1][col] = m.Name
-			if m.Price != -1 {
-				data[i + 1][col + 1] = fmt.Sprintf("%.2f€", float32(m.Price) / 100)
-			}
+			data[i + 1][col + 1] = m.PriceText()
 		}
 		col += 2
 	}
@@ -141,24 +132,22 @@ func (d *Day) WriteMarkdownTable(w io.Writer) error {
 		data[1][i] = ":-:"
 	}
 
-	keys := make([]string, 0, len(d.Meals))
-	for k := range d.Meals {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	
 	col := 0
-	for _, k := range keys {
+	for _, k := range d.sortedTypes() {
 		data[0][col] = k
 		v := d.Meals[k]
 		for i, m := range v {
 			if i >= len(data) - 2 {
 				data = append(data, make([]string, len(data[0])))
 			}
-			if m.Price != -1 {
-				data[i + 2][col] = fmt.Sprintf("%s *%.2f€*", m.Name, float32(m.Price) / 100)
+			name := m.Name
+			if m.Carbon != nil {
+				name = carbonEmojis[m.Carbon.Level] + " " + name
+			}
+			if price := m.PriceText(); price != "" {
+				data[i + 2][col] = fmt.Sprintf("%s *%s*", name, price)
 			} else {
-				data[i + 2][col] = m.Name
+				data[i + 2][col] = name
 			}
 		}
 		col += 1
@@ -168,4 +157,99 @@ func (d *Day) WriteMarkdownTable(w io.Writer) error {
 		fmt.Printf("|%s|\n", strings.Join(data[i], "|"))
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+func (d *Day) WriteHTML(w io.Writer) error {
+	type section struct {
+		Type  string
+		Meals []*Meal
+	}
+
+	sections := make([]section, 0, len(d.Meals))
+	for _, k := range d.sortedTypes() {
+		sections = append(sections, section{k, d.Meals[k]})
+	}
+
+	return dayHtmlTemplate.Execute(w, sections)
+}
+
+func (d *Day) ID() string {
+	return d.Start.Format(dayIdFormat)
+}
+
+func ParseDayID(id string) (time.Time, error) {
+	t, err := time.ParseInLocation(dayIdFormat, id, locale)
+	if err != nil {
+		return timeZero, ErrInvalidDayID
+	}
+
+	return t, nil
+}
+
+func (d *Day) Meal(id string) *Meal {
+	for _, meals := range d.Meals {
+		for _, m := range meals {
+			if m.ID == id {
+				return m
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *Day) identifyMeals() {
+	for _, c := range d.OrderedCategories() {
+		for _, m := range d.Meals[c.Label] {
+			m.identify(c.Category, d.Start)
+		}
+	}
+}
+
+func (d *Day) tagMeals() {
+	for _, meals := range d.Meals {
+		for _, m := range meals {
+			m.Components = ParseComponents(m.Name)
+			DefaultAllergenTagger.Tag(m)
+			DefaultDietClassifier.Tag(m)
+			m.Carbon = DefaultCarbonEstimator.Estimate(m)
+		}
+	}
+}
+
+func (d *Day) Filter(keep func(*Meal) bool) *Day {
+	meals := make(map[string][]*Meal, len(d.Meals))
+	for label, ms := range d.Meals {
+		for _, m := range ms {
+			if keep(m) {
+				meals[label] = append(meals[label], m)
+			}
+		}
+	}
+
+	filtered := new(Day)
+	filtered.Meals = meals
+	filtered.Categories = categorize(meals)
+	filtered.Start = d.Start
+	filtered.End = d.End
+
+	return filtered
+}
+
+func (d *Day) OrderedCategories() []*DayCategory {
+	if len(d.Categories) != len(d.Meals) {
+		d.Categories = categorize(d.Meals)
+	}
+
+	return d.Categories
+}
+
+func (d *Day) sortedTypes() []string {
+	categories := d.OrderedCategories()
+	keys := make([]string, 0, len(categories))
+	for _, c := range categories {
+		keys = append(keys, c.Label)
+	}
+
+	return keys
+}
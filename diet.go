@@ -0,0 +1,236 @@
+package gorldline
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+)
+
+const (
+	DietVegan       = "vegan"
+	DietVegetarian  = "vegetarian"
+	DietPescatarian = "pescatarian"
+	DietPorkFree    = "pork-free"
+	DietUnknown     = "unknown"
+
+	dietTagPrefix = "diet:"
+
+	dietPork       = "pork"
+	dietMeat       = "meat"
+	dietMeatDish   = "meat-dish"
+	dietFish       = "fish"
+	dietAnimal     = "animal"
+	dietPlant      = "plant"
+	dietPlantOnly  = "plant-only"
+	dietVegetarian = "vegetarian-marker"
+	dietVegan      = "vegan-marker"
+)
+
+var (
+	ErrUnknownDiet             = errors.New("unknown diet")
+	ErrInvalidDietOverrideFile = errors.New("invalid diet overrides file")
+)
+
+var (
+	Diets = []string{DietVegan, DietVegetarian, DietPescatarian, DietPorkFree, DietUnknown}
+
+	dietLexicon = map[string]string{
+		// Pork and pork-based dishes.
+		"porc": dietPork, "cochon": dietPork, "porcelet": dietPork, "jambon": dietPork, "lard": dietPork,
+		"lardon": dietPork, "bacon": dietPork, "saucisse": dietPork, "saucisson": dietPork, "chorizo": dietPork,
+		"andouille": dietPork, "andouillette": dietPork, "boudin": dietPork, "rillette": dietPork, "pâté": dietPork,
+		"knack": dietPork, "chipolata": dietPork, "coppa": dietPork, "pancetta": dietPork, "mortadelle": dietPork,
+		"travers": dietPork, "echine": dietPork, "sanglier": dietPork, "choucroute": dietPork,
+		"tartiflette": dietPork, "carbonara": dietPork, "quiche lorraine": dietPork, "cassoulet": dietPork,
+		"croque monsieur": dietPork, "cordon bleu": dietPork, "hot dog": dietPork, "potjevleesch": dietPork,
+		"tarte flambee": dietPork, "saucisse de volaille": dietMeat, "jambon de dinde": dietMeat, "lardons de volaille": dietMeat,
+		// Other meat.
+		"viande": dietMeat, "boeuf": dietMeat, "veau": dietMeat, "agneau": dietMeat, "mouton": dietMeat,
+		"poulet": dietMeat, "dinde": dietMeat, "canard": dietMeat, "volaille": dietMeat, "lapin": dietMeat,
+		"pintade": dietMeat, "caille": dietMeat, "chevreuil": dietMeat, "gibier": dietMeat, "foie": dietMeat,
+		"rognon": dietMeat, "tripe": dietMeat, "steak": dietMeat, "bavette": dietMeat, "entrecote": dietMeat,
+		"escalope": dietMeat, "cuisse": dietMeat, "aiguillette": dietMeat, "emince": dietMeat, "boulette": dietMeat,
+		"merguez": dietMeat, "kebab": dietMeat, "burger": dietMeat, "nugget": dietMeat, "hachis parmentier": dietMeat,
+		"bourguignon": dietMeat, "blanquette": dietMeat, "pot au feu": dietMeat, "carbonade": dietMeat,
+		"bolognaise": dietMeat, "chili con carne": dietMeat, "couscous royal": dietMeat, "osso buco": dietMeat,
+		"steak hache": dietMeat, "parmentier": dietMeat,
+		// Dishes made with meat unless another filling is named.
+		"lasagne": dietMeatDish, "moussaka": dietMeatDish, "cannelloni": dietMeatDish,
+		// Animal products that are fine for vegetarians.
+		"miel": dietAnimal, "fromage": dietAnimal, "oeuf": dietAnimal, "omelette": dietAnimal,
+		"beurre": dietAnimal, "creme": dietAnimal, "lait": dietAnimal, "yaourt": dietAnimal, "parmesan": dietAnimal,
+		"mozzarella": dietAnimal, "emmental": dietAnimal, "chevre": dietAnimal, "raclette": dietAnimal,
+		"mayonnaise": dietAnimal, "aioli": dietAnimal, "bechamel": dietAnimal,
+		// Dishes whose usual recipe implies butter, milk, cheese or eggs.
+		"tarte": dietAnimal, "tartelette": dietAnimal, "quiche": dietAnimal,
+		"puree": dietAnimal, "gratin": dietAnimal, "gratine": dietAnimal, "crepe": dietAnimal, "gaufre": dietAnimal,
+		"flan": dietAnimal, "clafoutis": dietAnimal, "crumble": dietAnimal, "gateau": dietAnimal, "cake": dietAnimal,
+		"brioche": dietAnimal, "beignet": dietAnimal, "mousse": dietAnimal, "cookie": dietAnimal, "muffin": dietAnimal,
+		"brownie": dietAnimal, "cesar": dietFish,
+		// Plant ingredients, they do not tell whether butter, cheese or stock was added.
+		"legume": dietPlant, "salade": dietPlant, "lentille": dietPlant, "pois chiche": dietPlant,
+		"haricot": dietPlant, "riz": dietPlant, "semoule": dietPlant, "boulgour": dietPlant, "quinoa": dietPlant,
+		"frite": dietPlant, "pomme de terre": dietPlant, "carotte": dietPlant, "courgette": dietPlant,
+		"epinard": dietPlant, "champignon": dietPlant, "brocoli": dietPlant, "chou": dietPlant, "poireau": dietPlant,
+		"tomate": dietPlant, "aubergine": dietPlant, "potage": dietPlant, "curry de legume": dietPlant,
+		// Plant-only dishes and fruits.
+		"crudite": dietPlantOnly, "ratatouille": dietPlantOnly, "falafel": dietPlantOnly, "tofu": dietPlantOnly,
+		"houmous": dietPlantOnly, "dahl": dietPlantOnly, "fruit": dietPlantOnly, "compote": dietPlantOnly,
+		"pomme": dietPlantOnly, "poire": dietPlantOnly, "orange": dietPlantOnly, "banane": dietPlantOnly,
+		"ananas": dietPlantOnly,
+		// Explicit markers.
+		"vegetarien": dietVegetarian, "vegetarienne": dietVegetarian, "veggie": dietVegetarian,
+		"vegetal": dietVegetarian, "vegetale": dietVegetarian,
+		"vegan": dietVegan, "vegane": dietVegan, "vegetalien": dietVegan, "vegetalienne": dietVegan,
+	}
+
+	DefaultDietClassifier = NewDietClassifier(dietLexicon)
+)
+
+type DietClassifier struct {
+	terms     *termMatcher
+	overrides map[string][]string
+}
+
+func NewDietClassifier(lexicon map[string]string) *DietClassifier {
+	c := new(DietClassifier)
+	c.terms = newTermMatcher()
+	c.overrides = make(map[string][]string)
+
+	for term, kind := range lexicon {
+		c.terms.add(term, []string{kind})
+	}
+
+	return c
+}
+
+func (c *DietClassifier) Override(name string, diets []string) error {
+	for _, d := range diets {
+		if !IsDiet(d) {
+			return ErrUnknownDiet
+		}
+	}
+
+	c.overrides[categoryKey(name)] = diets
+	return nil
+}
+
+func (c *DietClassifier) LoadOverridesFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var overrides map[string][]string
+	err = json.NewDecoder(file).Decode(&overrides)
+	if err != nil {
+		return ErrInvalidDietOverrideFile
+	}
+
+	for name, diets := range overrides {
+		err = c.Override(name, diets)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *DietClassifier) Classify(m *Meal) []string {
+	if diets, pres := c.overrides[categoryKey(m.Name)]; pres {
+		return diets
+	}
+
+	evidence := make(map[string]bool)
+	negated := make(map[string]bool)
+	for _, text := range m.componentTexts() {
+		for _, match := range c.terms.find(text) {
+			for _, kind := range match.values {
+				if match.negated {
+					negated[kind] = true
+				} else {
+					evidence[kind] = true
+				}
+			}
+		}
+	}
+
+	if m.HasAllergen(AllergenFish.ID) || m.HasAllergen(AllergenCrustaceans.ID) || m.HasAllergen(AllergenMolluscs.ID) {
+		evidence[dietFish] = true
+	}
+	if m.HasAllergen(AllergenEggs.ID) || m.HasAllergen(AllergenMilk.ID) {
+		evidence[dietAnimal] = true
+	}
+
+	switch m.Category {
+	case CategoryVegetableBar.ID:
+		evidence[dietPlant] = true
+	case CategoryCheese.ID:
+		evidence[dietAnimal] = true
+	}
+
+	// "Lasagnes" are made with beef, "Lasagnes aux légumes" or "Lasagnes de saumon" are not.
+	if evidence[dietMeatDish] && !evidence[dietFish] && !evidence[dietPlant] && !evidence[dietPlantOnly] {
+		evidence[dietMeat] = true
+	}
+
+	// "Saucisse sans porc" is pork-free even though sausages usually are not, it still is meat.
+	if negated[dietPork] && evidence[dietPork] {
+		evidence[dietPork] = false
+		evidence[dietMeat] = !negated[dietMeat]
+	}
+	if negated[dietMeat] {
+		evidence[dietMeat] = false
+	}
+
+	// Explicit markers win over ingredients, "Burger végétarien" is not a meat dish.
+	switch {
+	case evidence[dietVegan]:
+		return []string{DietVegan, DietVegetarian, DietPescatarian, DietPorkFree}
+	case evidence[dietVegetarian]:
+		return []string{DietVegetarian, DietPescatarian, DietPorkFree}
+	case evidence[dietPork]:
+		return []string{}
+	case evidence[dietMeat]:
+		return []string{DietPorkFree}
+	case evidence[dietFish]:
+		return []string{DietPescatarian, DietPorkFree}
+	case evidence[dietAnimal]:
+		return []string{DietVegetarian, DietPescatarian, DietPorkFree}
+	case evidence[dietPlantOnly]:
+		return []string{DietVegan, DietVegetarian, DietPescatarian, DietPorkFree}
+	case negated[dietPork]:
+		return []string{DietPorkFree}
+	case negated[dietMeat]:
+		return []string{DietPescatarian, DietPorkFree}
+	default:
+		return []string{DietUnknown}
+	}
+}
+
+func (c *DietClassifier) Tag(m *Meal) {
+	for tag := range m.Tags {
+		if strings.HasPrefix(tag, dietTagPrefix) {
+			m.Tags.Remove(tag)
+		}
+	}
+
+	m.Diets = c.Classify(m)
+	for _, d := range m.Diets {
+		m.Tags.Add(dietTagPrefix + d)
+	}
+}
+
+func (m *Meal) HasDiet(diet string) bool {
+	return m.Tags.Has(dietTagPrefix + diet)
+}
+
+func IsDiet(diet string) bool {
+	return containsString(Diets, diet)
+}
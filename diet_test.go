@@ -0,0 +1,59 @@
+package gorldline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func taggedMeal(name, category string) *Meal {
+	m := &Meal{Name: name, Category: category, Tags: make(Tags)}
+	d := &Day{Meals: map[string][]*Meal{category: {m}}}
+	d.tagMeals()
+
+	return m
+}
+
+func TestDietClassifier(t *testing.T) {
+	var (
+		vegan       = []string{DietVegan, DietVegetarian, DietPescatarian, DietPorkFree}
+		vegetarian  = []string{DietVegetarian, DietPescatarian, DietPorkFree}
+		pescatarian = []string{DietPescatarian, DietPorkFree}
+		meat        = []string{DietPorkFree}
+		pork        = []string{}
+		unknown     = []string{DietUnknown}
+	)
+
+	cases := []struct {
+		name string
+		want []string
+	}{
+		{"Tarte aux pommes", vegetarian},
+		{"Salade César", pescatarian},
+		{"Salade César au poulet", meat},
+		{"Purée de pommes de terre", vegetarian},
+		{"Gratin de courgettes", vegetarian},
+		{"Crêpe au sucre", vegetarian},
+		{"Lasagnes aux légumes", vegetarian},
+		{"Lasagnes de saumon", pescatarian},
+		{"Lasagnes", meat},
+		{"Tarte flambée", pork},
+		{"Riz pilaf", unknown},
+		{"Frites", unknown},
+		{"Ratatouille", vegan},
+		{"Compote de pommes", vegan},
+		{"Salade de fruits", vegan},
+		{"Burger végétarien", vegetarian},
+		{"Curry de légumes vegan", vegan},
+		{"Filet de colin", pescatarian},
+		{"Saucisse sans porc, purée", meat},
+		{"Choucroute garnie", pork},
+		{"Blanquette de veau", meat},
+	}
+
+	for _, c := range cases {
+		m := taggedMeal(c.name, CategoryDailyDish.ID)
+		if !reflect.DeepEqual(m.Diets, c.want) {
+			t.Errorf("Classify(%q) = %v, want %v", c.name, m.Diets, c.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package gorldline
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+)
+
+const (
+	DefaultEinkWidth  = 800
+	DefaultEinkHeight = 480
+	MaxEinkSize       = 2048
+
+	bmpFileHeaderSize = 14
+	bmpInfoHeaderSize = 40
+)
+
+var (
+	ErrInvalidEinkSize = errors.New("invalid e-ink display size")
+)
+
+var (
+	EinkBlackWhite = EinkPalette{
+		color.RGBA{0xff, 0xff, 0xff, 0xff},
+		color.RGBA{0x00, 0x00, 0x00, 0xff},
+	}
+	EinkBlackWhiteRed = EinkPalette{
+		color.RGBA{0xff, 0xff, 0xff, 0xff},
+		color.RGBA{0x00, 0x00, 0x00, 0xff},
+		color.RGBA{0xff, 0x00, 0x00, 0xff},
+	}
+	EinkBlackWhiteYellow = EinkPalette{
+		color.RGBA{0xff, 0xff, 0xff, 0xff},
+		color.RGBA{0x00, 0x00, 0x00, 0xff},
+		color.RGBA{0xff, 0xff, 0x00, 0xff},
+	}
+)
+
+type EinkPalette []color.Color
+
+type EinkOptions struct {
+	Width   int
+	Height  int
+	Palette EinkPalette
+}
+
+func (d *Day) WriteEinkBMP(w io.Writer, opts *EinkOptions) error {
+	o := EinkOptions{}
+	if opts != nil {
+		o = *opts
+	}
+
+	if o.Width == 0 && o.Height == 0 {
+		o.Width, o.Height = DefaultEinkWidth, DefaultEinkHeight
+	}
+	if o.Width <= 0 || o.Height <= 0 || o.Width > MaxEinkSize || o.Height > MaxEinkSize {
+		return ErrInvalidEinkSize
+	}
+	if len(o.Palette) < 2 {
+		o.Palette = EinkBlackWhite
+	}
+
+	// Highlight colour is only used for category bands, everything else stays black on white for contrast.
+	accent := o.Palette[1]
+	if len(o.Palette) > 2 {
+		accent = o.Palette[2]
+	}
+
+	size := float64(o.Width) / 40
+	if h := float64(o.Height) / 20; h < size {
+		size = h
+	}
+	if size < 10 {
+		size = 10
+	}
+
+	c, err := newDaysCanvas([]*Day{d}, ImageOptions{
+		Width:    o.Width,
+		Height:   o.Height,
+		FontSize: size,
+		Theme: &ImageTheme{
+			Background: o.Palette[0],
+			Foreground: o.Palette[1],
+			Muted:      o.Palette[1],
+			Header:     o.Palette[0],
+			Palette:    []color.Color{accent},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	img, err := c.rasterize()
+	if err != nil {
+		return err
+	}
+
+	return writePalettedBMP(w, img, o.Palette)
+}
+
+func writePalettedBMP(w io.Writer, img image.Image, palette EinkPalette) error {
+	bpp := 1
+	if len(palette) > 2 {
+		bpp = 4
+	}
+
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	rowSize := ((width*bpp + 31) / 32) * 4
+	paletteSize := 4 * (1 << uint(bpp))
+	offset := bmpFileHeaderSize + bmpInfoHeaderSize + paletteSize
+
+	header := make([]byte, offset)
+	header[0], header[1] = 'B', 'M'
+	binary.LittleEndian.PutUint32(header[2:], uint32(offset+rowSize*height))
+	binary.LittleEndian.PutUint32(header[10:], uint32(offset))
+
+	info := header[bmpFileHeaderSize:]
+	binary.LittleEndian.PutUint32(info[0:], bmpInfoHeaderSize)
+	binary.LittleEndian.PutUint32(info[4:], uint32(width))
+	binary.LittleEndian.PutUint32(info[8:], uint32(height))
+	binary.LittleEndian.PutUint16(info[12:], 1)
+	binary.LittleEndian.PutUint16(info[14:], uint16(bpp))
+	binary.LittleEndian.PutUint32(info[20:], uint32(rowSize*height))
+	binary.LittleEndian.PutUint32(info[32:], uint32(len(palette)))
+
+	for i, c := range palette {
+		r, g, bl, _ := c.RGBA()
+		entry := header[bmpFileHeaderSize+bmpInfoHeaderSize+4*i:]
+		entry[0], entry[1], entry[2] = byte(bl>>8), byte(g>>8), byte(r>>8)
+	}
+
+	_, err := w.Write(header)
+	if err != nil {
+		return err
+	}
+
+	row := make([]byte, rowSize)
+	for y := b.Max.Y - 1; y >= b.Min.Y; y-- {
+		for i := range row {
+			row[i] = 0
+		}
+
+		for x := b.Min.X; x < b.Max.X; x++ {
+			index := palette.index(img.At(x, y))
+			bit := (x - b.Min.X) * bpp
+			row[bit/8] |= index << uint(8-bpp-bit%8)
+		}
+
+		_, err := w.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Anti-aliased edges of black text are grey, they must not snap to red or yellow and leave coloured fringes
+// around every glyph. An accent is only used for pixels that are at least half as saturated as it.
+func (p EinkPalette) index(c color.Color) byte {
+	best, bestDistance := 0, -1
+	for i, entry := range p {
+		if i >= 2 && 2*chroma(c) < chroma(entry) {
+			continue
+		}
+
+		if d := colorDistance(c, entry); bestDistance == -1 || d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+
+	return byte(best)
+}
+
+func chroma(c color.Color) int {
+	r, g, b, _ := c.RGBA()
+	max, min := r, r
+	for _, v := range []uint32{g, b} {
+		if v > max {
+			max = v
+		}
+		if v < min {
+			min = v
+		}
+	}
+
+	return int(max>>8) - int(min>>8)
+}
+
+func colorDistance(a, b color.Color) int {
+	ar, ag, ab, _ := a.RGBA()
+	br, bg, bb, _ := b.RGBA()
+	dr, dg, db := int(ar>>8)-int(br>>8), int(ag>>8)-int(bg>>8), int(ab>>8)-int(bb>>8)
+
+	return dr*dr + dg*dg + db*db
+}
@@ -0,0 +1,94 @@
+package gorldline
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestEinkPaletteIndex(t *testing.T) {
+	cases := []struct {
+		palette EinkPalette
+		color   color.Color
+		want    byte
+	}{
+		{EinkBlackWhiteRed, color.RGBA{0xff, 0xff, 0xff, 0xff}, 0},
+		{EinkBlackWhiteRed, color.RGBA{0x00, 0x00, 0x00, 0xff}, 1},
+		{EinkBlackWhiteRed, color.RGBA{0xff, 0x00, 0x00, 0xff}, 2},
+		{EinkBlackWhiteRed, color.RGBA{0x80, 0x80, 0x80, 0xff}, 0},
+		{EinkBlackWhiteRed, color.RGBA{0x60, 0x60, 0x60, 0xff}, 1},
+		{EinkBlackWhiteRed, color.RGBA{0xc0, 0x10, 0x10, 0xff}, 2},
+		{EinkBlackWhiteYellow, color.RGBA{0xa0, 0xa0, 0xa0, 0xff}, 0},
+		{EinkBlackWhiteYellow, color.RGBA{0x40, 0x40, 0x40, 0xff}, 1},
+		{EinkBlackWhiteYellow, color.RGBA{0xf0, 0xe0, 0x10, 0xff}, 2},
+		{EinkBlackWhite, color.RGBA{0xff, 0x00, 0x00, 0xff}, 1},
+	}
+
+	for _, c := range cases {
+		if got := c.palette.index(c.color); got != c.want {
+			t.Errorf("index(%v) with %d colours = %d, want %d", c.color, len(c.palette), got, c.want)
+		}
+	}
+}
+
+func TestEinkTextHasNoAccentFringes(t *testing.T) {
+	d := &Day{Start: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), Meals: map[string][]*Meal{
+		"Plat du jour": {{Name: "Poulet rôti, frites", Tags: make(Tags)}},
+		"Dessert":      {{Name: "Tarte aux pommes", Tags: make(Tags)}},
+	}}
+
+	for _, palette := range []EinkPalette{EinkBlackWhiteRed, EinkBlackWhiteYellow} {
+		c, err := newDaysCanvas([]*Day{d}, ImageOptions{
+			Width:    DefaultEinkWidth,
+			Height:   DefaultEinkHeight,
+			FontSize: 20,
+			Theme: &ImageTheme{
+				Background: palette[0],
+				Foreground: palette[1],
+				Muted:      palette[1],
+				Header:     palette[0],
+				Palette:    []color.Color{palette[2]},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := c.rasterize()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		accented := make([]image.Rectangle, 0)
+		for _, r := range c.rects {
+			if r.color == palette[2] {
+				accented = append(accented, image.Rect(r.x, r.y, r.x+r.w, r.y+r.h))
+			}
+		}
+
+		fringes := 0
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if palette.index(img.At(x, y)) != 2 || inRectangles(image.Pt(x, y), accented) {
+					continue
+				}
+				fringes++
+			}
+		}
+		if fringes > 0 {
+			t.Errorf("%d accent pixels outside the coloured bands with %v", fringes, palette[2])
+		}
+	}
+}
+
+func inRectangles(p image.Point, rects []image.Rectangle) bool {
+	for _, r := range rects {
+		if p.In(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,547 @@
+package gorldline
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	profileTokenLength = 24
+)
+
+var (
+	ErrInvalidProfile      = errors.New("invalid profile")
+	ErrProfileNotFound     = errors.New("profile not found")
+	ErrInvalidProfileFile  = errors.New("invalid profiles file")
+	ErrNotificationFailed  = errors.New("notification backend rejected the alert")
+	ErrInvalidProfileToken = errors.New("invalid profile token")
+	ErrForbiddenWebhook    = errors.New("webhook address not allowed")
+)
+
+var (
+	// Ranges that are not covered by net.IP helpers but never host a public webhook.
+	reservedNetworks = []*net.IPNet{
+		mustParseCIDR("0.0.0.0/8"),
+		mustParseCIDR("100.64.0.0/10"),
+		mustParseCIDR("198.18.0.0/15"),
+	}
+
+	alertFormats = map[string]string{
+		"fr": "%[1]s ce %[2]s !",
+		"en": "%[1]s on %[2]s!",
+		"de": "%[1]s am %[2]s!",
+		"es": "¡%[1]s el %[2]s!",
+	}
+)
+
+type Profile struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Language   string   `json:"language"`
+	Favourites []string `json:"favourites"`
+	Webhook    string   `json:"webhook,omitempty"`
+	Notified   []string `json:"notified,omitempty"`
+	TokenHash  string   `json:"tokenHash,omitempty"`
+}
+
+// The token is only known by the owner of the profile, the store keeps a hash of it.
+func NewProfileToken() (string, error) {
+	b := make([]byte, profileTokenLength)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func (p *Profile) SetToken(token string) {
+	p.TokenHash = hashUserToken(token)
+}
+
+func (p *Profile) CheckToken(token string) bool {
+	if p.TokenHash == "" || strings.TrimSpace(token) == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(p.TokenHash), []byte(hashUserToken(token))) == 1
+}
+
+func (p *Profile) Public() *Profile {
+	copied := *p
+	copied.TokenHash = ""
+	return &copied
+}
+
+type FavouriteMatch struct {
+	Favourite string `json:"favourite"`
+	Meal      *Meal  `json:"meal"`
+}
+
+func (m *FavouriteMatch) Message(l *Language) string {
+	if l == nil {
+		l = French
+	}
+
+	return fmt.Sprintf(alertFormats[l.Code], m.Meal.Name, l.weekdays[m.Meal.Date.Weekday()])
+}
+
+func (p *Profile) Matches(days []*Day) []*FavouriteMatch {
+	matches := make([]*FavouriteMatch, 0)
+	for _, d := range days {
+		for _, c := range d.OrderedCategories() {
+			for _, m := range d.Meals[c.Label] {
+				for _, f := range p.Favourites {
+					if MatchFavourite(f, m.Name) {
+						matches = append(matches, &FavouriteMatch{f, m})
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+// Every word of the favourite must be found in the name, allowing a typo in longer words.
+func MatchFavourite(favourite, name string) bool {
+	wanted := searchTerms(favourite)
+	if len(wanted) == 0 {
+		return false
+	}
+
+	terms := searchTerms(name)
+	for _, w := range wanted {
+		found := false
+		for _, t := range terms {
+			if t == w || levenshtein(t, w) <= allowedTypos(w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func allowedTypos(word string) int {
+	switch n := len([]rune(word)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, minInt(curr[j-1]+1, prev[j-1]+cost))
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+type ProfileStore struct {
+	path     string
+	lock     sync.Mutex
+	alerting sync.Mutex
+	profiles map[string]*Profile
+}
+
+func NewProfileStore(path string) (*ProfileStore, error) {
+	s := new(ProfileStore)
+	s.path = path
+	s.profiles = make(map[string]*Profile)
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var profiles []*Profile
+	err = json.NewDecoder(file).Decode(&profiles)
+	if err != nil {
+		return nil, ErrInvalidProfileFile
+	}
+
+	for _, p := range profiles {
+		s.profiles[p.ID] = p
+	}
+
+	return s, nil
+}
+
+func (s *ProfileStore) Get(id string) (*Profile, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	p, pres := s.profiles[id]
+	if !pres {
+		return nil, ErrProfileNotFound
+	}
+
+	copied := *p
+	return &copied, nil
+}
+
+func (s *ProfileStore) Authorize(id, token string) (*Profile, error) {
+	p, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.CheckToken(token) {
+		return nil, ErrInvalidProfileToken
+	}
+
+	return p, nil
+}
+
+func (s *ProfileStore) List() []*Profile {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	profiles := make([]*Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		copied := *p
+		profiles = append(profiles, &copied)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].ID < profiles[j].ID
+	})
+
+	return profiles
+}
+
+func (s *ProfileStore) Put(p *Profile) error {
+	if p.ID == "" {
+		return ErrInvalidProfile
+	}
+	if p.Language == "" {
+		p.Language = French.Code
+	}
+	if LanguageByCode(p.Language) == nil {
+		return ErrInvalidProfile
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	copied := *p
+	s.profiles[p.ID] = &copied
+	return s.save()
+}
+
+func (s *ProfileStore) Delete(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, pres := s.profiles[id]; !pres {
+		return ErrProfileNotFound
+	}
+
+	delete(s.profiles, id)
+	return s.save()
+}
+
+// Alert notifies every profile of its upcoming matches, each meal being notified once.
+func (s *ProfileStore) Alert(days []*Day, n Notifier) error {
+	today := midnight(time.Now())
+	upcoming := make([]*Day, 0, len(days))
+	ids := make([]string, 0)
+	for _, d := range days {
+		if d.End.Before(today) {
+			continue
+		}
+
+		upcoming = append(upcoming, d)
+		for _, meals := range d.Meals {
+			for _, m := range meals {
+				ids = append(ids, m.ID)
+			}
+		}
+	}
+
+	// Only one round of alerts runs at a time, so that a meal is never notified twice.
+	s.alerting.Lock()
+	defer s.alerting.Unlock()
+
+	type pending struct {
+		profile *Profile
+		fresh   []*FavouriteMatch
+	}
+
+	// Notifiers may be slow, the store is only locked to read and update the profiles.
+	s.lock.Lock()
+	queue := make([]pending, 0)
+	for _, p := range s.profiles {
+		// Past meals can never match again, there is no need to remember them.
+		notified := make([]string, 0, len(p.Notified))
+		for _, id := range p.Notified {
+			if containsString(ids, id) {
+				notified = append(notified, id)
+			}
+		}
+		p.Notified = notified
+
+		fresh := make([]*FavouriteMatch, 0)
+		for _, m := range p.Matches(upcoming) {
+			if !containsString(p.Notified, m.Meal.ID) {
+				fresh = append(fresh, m)
+			}
+		}
+		if len(fresh) > 0 {
+			copied := *p
+			queue = append(queue, pending{&copied, fresh})
+		}
+	}
+	s.lock.Unlock()
+
+	var lastErr error
+	sent := make(map[string][]*FavouriteMatch)
+	for _, q := range queue {
+		err := n.Notify(q.profile, q.fresh)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		sent[q.profile.ID] = q.fresh
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for id, fresh := range sent {
+		p, pres := s.profiles[id]
+		if !pres {
+			continue
+		}
+		for _, m := range fresh {
+			if !containsString(p.Notified, m.Meal.ID) {
+				p.Notified = append(p.Notified, m.Meal.ID)
+			}
+		}
+	}
+
+	err := s.save()
+	if err != nil {
+		return err
+	}
+
+	return lastErr
+}
+
+func (s *ProfileStore) save() error {
+	profiles := make([]*Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].ID < profiles[j].ID
+	})
+
+	return writeJsonFile(s.path, profiles)
+}
+
+type Notifier interface {
+	Notify(p *Profile, matches []*FavouriteMatch) error
+}
+
+type WriterNotifier struct {
+	Writer io.Writer
+}
+
+func (n *WriterNotifier) Notify(p *Profile, matches []*FavouriteMatch) error {
+	l := LanguageByCode(p.Language)
+	for _, m := range matches {
+		_, err := fmt.Fprintf(n.Writer, "%s: %s\n", p.ID, m.Message(l))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Webhooks are user supplied, they must use HTTPS and never reach a private address.
+type WebhookNotifier struct {
+	AllowedHosts []string
+	client       *http.Client
+}
+
+func NewWebhookNotifier(allowedHosts []string, timeout time.Duration) *WebhookNotifier {
+	n := new(WebhookNotifier)
+	n.AllowedHosts = allowedHosts
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !isPublicIP(net.ParseIP(host)) {
+				return ErrForbiddenWebhook
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+	}
+	n.client = &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return n
+}
+
+func (n *WebhookNotifier) Validate(webhook string) error {
+	if webhook == "" {
+		return nil
+	}
+
+	u, err := url.Parse(webhook)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return ErrForbiddenWebhook
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return ErrForbiddenWebhook
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenWebhook
+	}
+
+	if len(n.AllowedHosts) > 0 && !containsString(n.AllowedHosts, host) {
+		return ErrForbiddenWebhook
+	}
+
+	return nil
+}
+
+func (n *WebhookNotifier) Notify(p *Profile, matches []*FavouriteMatch) error {
+	if p.Webhook == "" {
+		return nil
+	}
+
+	err := n.Validate(p.Webhook)
+	if err != nil {
+		return err
+	}
+
+	l := LanguageByCode(p.Language)
+	payload := struct {
+		Profile string            `json:"profile"`
+		Text    string            `json:"text"`
+		Matches []*FavouriteMatch `json:"matches"`
+	}{p.ID, "", matches}
+
+	for i, m := range matches {
+		if i > 0 {
+			payload.Text += "\n"
+		}
+		payload.Text += m.Message(l)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	client := n.client
+	if client == nil {
+		client = NewWebhookNotifier(n.AllowedHosts, 10*time.Second).client
+	}
+
+	res, err := client.Post(p.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	_ = res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return ErrNotificationFailed
+	}
+
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+
+	for _, n := range reservedNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mustParseCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return n
+}
@@ -0,0 +1,56 @@
+package gorldline
+
+import (
+	"testing"
+)
+
+func TestMatchFavourite(t *testing.T) {
+	cases := []struct {
+		favourite string
+		name      string
+		want      bool
+	}{
+		{"lasagne", "Lasagnes bolognaise", true},
+		{"Lasagnes", "lasagne au saumon", true},
+		{"bourguignon", "Boeuf bourgignon", true},
+		{"boeuf bourguignon", "Bœuf bourguignon, pâtes", true},
+		{"boeuf bourguignon", "Boeuf braisé", false},
+		{"poulet roti", "Poulet rôti, frites", true},
+		{"frites", "Fritas", true},
+		{"riz", "Ris de veau", false},
+		{"pâté", "Pâtes carbonara", false},
+		{"couscous", "Couscous royal", true},
+		{"tarte", "Tartiflette", false},
+		{"", "Poulet rôti", false},
+		{"de la", "Poulet rôti", false},
+	}
+
+	for _, c := range cases {
+		if got := MatchFavourite(c.favourite, c.name); got != c.want {
+			t.Errorf("MatchFavourite(%q, %q) = %v, want %v", c.favourite, c.name, got, c.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"riz", "", 3},
+		{"", "riz", 3},
+		{"poulet", "poulet", 0},
+		{"bourguignon", "bourgignon", 1},
+		{"frites", "fritas", 1},
+		{"chat", "chien", 3},
+		{"pâte", "pate", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, c := range cases {
+		if got := levenshtein(c.a, c.b); got != c.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package gorldline
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	FeedTitle = "Worldline - Seclin - Menu"
+
+	jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+)
+
+func NewDaysFeed(l *List) (*Feed, error) {
+	f := newFeed()
+
+	for _, week := range l.Weeks {
+		days, err := week.GetDays()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range days {
+			var b strings.Builder
+			err := d.WriteHTML(&b)
+			if err != nil {
+				return nil, err
+			}
+
+			e := new(FeedEntry)
+			e.ID = fmt.Sprintf("%s#%s", week.LinkOrPath, d.Start.Format("2006-01-02"))
+			e.Title = fmt.Sprintf("Menu du %s", formatDate(d.Start))
+			e.Link = week.LinkOrPath
+			e.Content = b.String()
+			e.Date = d.Start
+
+			f.addEntry(e)
+		}
+	}
+
+	f.sortEntries()
+	f.SetUpdated(time.Now())
+	return f, nil
+}
+
+func NewWeeksFeed(l *List) (*Feed, error) {
+	f := newFeed()
+
+	for _, week := range l.Weeks {
+		days, err := week.GetDays()
+		if err != nil {
+			return nil, err
+		}
+
+		var b strings.Builder
+		for _, d := range days {
+			_, _ = fmt.Fprintf(&b, "<h2>%s</h2>\n", formatDate(d.Start))
+			err := d.WriteHTML(&b)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		e := new(FeedEntry)
+		e.ID = week.LinkOrPath
+		e.Title = week.title()
+		e.Link = week.LinkOrPath
+		e.Content = b.String()
+		e.Date = week.Start
+
+		f.addEntry(e)
+	}
+
+	f.sortEntries()
+	f.SetUpdated(time.Now())
+	return f, nil
+}
+
+func newFeed() *Feed {
+	f := new(Feed)
+	f.Title = FeedTitle
+	f.Link = DefaultBaseUrl + MenusUri
+	f.Entries = make([]*FeedEntry, 0)
+
+	return f
+}
+
+// Date is the menu day, it usually lies in the future and only orders the entries.
+// Updated is when the menu was fetched, which is what readers and conditional requests expect.
+type FeedEntry struct {
+	ID      string
+	Title   string
+	Link    string
+	Content string
+	Date    time.Time
+	Updated time.Time
+}
+
+type Feed struct {
+	Title   string
+	Link    string
+	SelfUrl string
+	Updated time.Time
+	Entries []*FeedEntry
+}
+
+func (f *Feed) addEntry(e *FeedEntry) {
+	f.Entries = append(f.Entries, e)
+}
+
+func (f *Feed) sortEntries() {
+	sort.SliceStable(f.Entries, func(i, j int) bool {
+		return f.Entries[i].Date.After(f.Entries[j].Date)
+	})
+}
+
+func (f *Feed) SetUpdated(t time.Time) {
+	f.Updated = t
+	for _, e := range f.Entries {
+		e.Updated = t
+	}
+}
+
+// Digest identifies the content of the entries, regardless of when they were fetched.
+func (f *Feed) Digest() string {
+	h := sha1.New()
+	for _, e := range f.Entries {
+		_, _ = fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", e.ID, e.Title, e.Link, e.Content)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Content atomContent `xml:"content"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Links   []atomLink  `xml:"link"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+func (f *Feed) WriteAtom(w io.Writer) error {
+	feed := atomFeed{
+		ID:      f.Link,
+		Title:   f.Title,
+		Links:   []atomLink{{Href: f.Link}},
+		Updated: f.Updated.Format(time.RFC3339),
+		Entries: make([]atomEntry, 0, len(f.Entries)),
+	}
+
+	if f.SelfUrl != "" {
+		feed.Links = append(feed.Links, atomLink{Href: f.SelfUrl, Rel: "self"})
+	}
+
+	for _, e := range f.Entries {
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:      e.ID,
+			Title:   e.Title,
+			Link:    atomLink{Href: e.Link},
+			Updated: e.Updated.Format(time.RFC3339),
+			Content: atomContent{Type: "html", Body: e.Content},
+		})
+	}
+
+	return writeXml(w, feed)
+}
+
+type rssGuid struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Guid        rssGuid `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssFeed struct {
+	XMLName       xml.Name  `xml:"rss"`
+	Version       string    `xml:"version,attr"`
+	Title         string    `xml:"channel>title"`
+	Link          string    `xml:"channel>link"`
+	Description   string    `xml:"channel>description"`
+	LastBuildDate string    `xml:"channel>lastBuildDate"`
+	Items         []rssItem `xml:"channel>item"`
+}
+
+func (f *Feed) WriteRSS(w io.Writer) error {
+	feed := rssFeed{
+		Version:       "2.0",
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Title,
+		LastBuildDate: f.Updated.Format(time.RFC1123Z),
+		Items:         make([]rssItem, 0, len(f.Entries)),
+	}
+
+	for _, e := range f.Entries {
+		feed.Items = append(feed.Items, rssItem{
+			Title:       e.Title,
+			Link:        e.Link,
+			Guid:        rssGuid{Value: e.ID},
+			PubDate:     e.Updated.Format(time.RFC1123Z),
+			Description: e.Content,
+		})
+	}
+
+	return writeXml(w, feed)
+}
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	Url           string `json:"url"`
+	Title         string `json:"title"`
+	ContentHtml   string `json:"content_html"`
+	DatePublished string `json:"date_published"`
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageUrl string         `json:"home_page_url"`
+	FeedUrl     string         `json:"feed_url,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+func (f *Feed) WriteJSON(w io.Writer) error {
+	feed := jsonFeed{
+		Version:     jsonFeedVersion,
+		Title:       f.Title,
+		HomePageUrl: f.Link,
+		FeedUrl:     f.SelfUrl,
+		Items:       make([]jsonFeedItem, 0, len(f.Entries)),
+	}
+
+	for _, e := range f.Entries {
+		feed.Items = append(feed.Items, jsonFeedItem{
+			ID:            e.ID,
+			Url:           e.Link,
+			Title:         e.Title,
+			ContentHtml:   e.Content,
+			DatePublished: e.Updated.Format(time.RFC3339),
+		})
+	}
+
+	return json.NewEncoder(w).Encode(feed)
+}
+
+func writeXml(w io.Writer, v interface{}) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	return enc.Encode(v)
+}
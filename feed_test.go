@@ -0,0 +1,44 @@
+package gorldline
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeedDatesAndDigest(t *testing.T) {
+	fetched := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	f := newFeed()
+	for _, day := range []int{5, 7, 6} {
+		f.addEntry(&FeedEntry{
+			ID:      strings.Repeat("x", day),
+			Title:   "Menu",
+			Content: "<ul></ul>",
+			Date:    time.Date(2026, 10, day, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	f.sortEntries()
+	f.SetUpdated(fetched)
+
+	for i, day := range []int{7, 6, 5} {
+		if got := f.Entries[i].Date.Day(); got != day {
+			t.Errorf("entry %d is for day %d, want %d", i, got, day)
+		}
+		if !f.Entries[i].Updated.Equal(fetched) {
+			t.Errorf("entry %d updated %v, want the fetch time %v", i, f.Entries[i].Updated, fetched)
+		}
+	}
+	if !f.Updated.Equal(fetched) {
+		t.Errorf("feed updated %v, want %v", f.Updated, fetched)
+	}
+
+	digest := f.Digest()
+	f.SetUpdated(fetched.Add(time.Hour))
+	if f.Digest() != digest {
+		t.Error("digest changed with the fetch time")
+	}
+	f.Entries[0].Content = "<ul><li>Frites</li></ul>"
+	if f.Digest() == digest {
+		t.Error("digest did not change with the content")
+	}
+}
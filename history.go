@@ -0,0 +1,92 @@
+package gorldline
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"time"
+)
+
+var (
+	ErrInvalidHistoryFile = errors.New("invalid history file")
+)
+
+func LoadHistoryFile(path string) ([]*Day, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Day{}, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var days []*Day
+	err = json.NewDecoder(file).Decode(&days)
+	if err != nil {
+		return nil, ErrInvalidHistoryFile
+	}
+
+	for _, d := range days {
+		d.Categories = categorize(d.Meals)
+	}
+
+	return days, nil
+}
+
+// Days already in the history are replaced by the given ones, so archiving the same week twice is harmless.
+func SaveHistoryFile(path string, days []*Day) error {
+	history, err := LoadHistoryFile(path)
+	if err != nil {
+		return err
+	}
+
+	byId := make(map[string]*Day, len(history)+len(days))
+	for _, d := range append(history, days...) {
+		byId[d.ID()] = d
+	}
+
+	merged := make([]*Day, 0, len(byId))
+	for _, d := range byId {
+		merged = append(merged, d)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Start.Before(merged[j].Start)
+	})
+
+	return writeJsonFile(path, merged)
+}
+
+func (l *List) Days() ([]*Day, error) {
+	days := make([]*Day, 0)
+	for _, w := range l.Weeks {
+		wd, err := w.GetDays()
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, wd...)
+	}
+
+	return days, nil
+}
+
+func (l *List) UpcomingDays(now time.Time) ([]*Day, error) {
+	days, err := l.Days()
+	if err != nil {
+		return nil, err
+	}
+
+	today := midnight(now)
+	upcoming := make([]*Day, 0, len(days))
+	for _, d := range days {
+		if !d.End.Before(today) {
+			upcoming = append(upcoming, d)
+		}
+	}
+
+	return upcoming, nil
+}
@@ -0,0 +1,467 @@
+package gorldline
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	DefaultImageWidth    = 800
+	MaxImageSize         = 4096
+	DefaultImageFontSize = 18
+	MaxImageFontSize     = 96
+
+	imageMargin  = 24
+	imagePadding = 8
+	svgFontStack = "Go, Helvetica, Arial, sans-serif"
+)
+
+var (
+	ErrInvalidImageSize = errors.New("invalid image size")
+)
+
+var (
+	LightImageTheme = &ImageTheme{
+		Background: color.RGBA{0xff, 0xff, 0xff, 0xff},
+		Foreground: color.RGBA{0x22, 0x22, 0x22, 0xff},
+		Muted:      color.RGBA{0x66, 0x66, 0x66, 0xff},
+		Header:     color.RGBA{0xff, 0xff, 0xff, 0xff},
+		Palette: []color.Color{
+			color.RGBA{0x00, 0x66, 0xa1, 0xff},
+			color.RGBA{0xd3, 0x54, 0x00, 0xff},
+			color.RGBA{0x27, 0xae, 0x60, 0xff},
+			color.RGBA{0x8e, 0x44, 0xad, 0xff},
+			color.RGBA{0xc0, 0x39, 0x2b, 0xff},
+			color.RGBA{0x16, 0xa0, 0x85, 0xff},
+		},
+	}
+	DarkImageTheme = &ImageTheme{
+		Background: color.RGBA{0x1e, 0x1e, 0x1e, 0xff},
+		Foreground: color.RGBA{0xee, 0xee, 0xee, 0xff},
+		Muted:      color.RGBA{0xaa, 0xaa, 0xaa, 0xff},
+		Header:     color.RGBA{0xff, 0xff, 0xff, 0xff},
+		Palette:    LightImageTheme.Palette,
+	}
+)
+
+var (
+	fontsOnce   sync.Once
+	fontsErr    error
+	regularFont *opentype.Font
+	boldFont    *opentype.Font
+)
+
+type ImageTheme struct {
+	Background color.Color
+	Foreground color.Color
+	Muted      color.Color
+	Header     color.Color
+	Palette    []color.Color
+	Categories map[string]color.Color
+}
+
+func (t *ImageTheme) categoryColor(category *DayCategory, index int) color.Color {
+	if c, pres := t.Categories[category.ID]; pres {
+		return c
+	}
+	if c, pres := t.Categories[category.Label]; pres {
+		return c
+	}
+
+	if len(t.Palette) == 0 {
+		return t.Muted
+	}
+
+	return t.Palette[index%len(t.Palette)]
+}
+
+type ImageOptions struct {
+	Width    int
+	Height   int
+	FontSize float64
+	Theme    *ImageTheme
+}
+
+func (o *ImageOptions) withDefaults() (ImageOptions, error) {
+	opts := ImageOptions{}
+	if o != nil {
+		opts = *o
+	}
+
+	if opts.Width < 0 || opts.Width > MaxImageSize || opts.Height < 0 || opts.Height > MaxImageSize {
+		return opts, ErrInvalidImageSize
+	}
+	if opts.FontSize < 0 || opts.FontSize > MaxImageFontSize {
+		return opts, ErrInvalidImageSize
+	}
+
+	if opts.Width == 0 {
+		opts.Width = DefaultImageWidth
+	}
+	if opts.FontSize == 0 {
+		opts.FontSize = DefaultImageFontSize
+	}
+	if opts.Theme == nil {
+		opts.Theme = LightImageTheme
+	}
+
+	return opts, nil
+}
+
+type imageFaceKey struct {
+	bold bool
+	size float64
+}
+
+type imageFaces map[imageFaceKey]font.Face
+
+type imageRect struct {
+	x, y, w, h int
+	color      color.Color
+}
+
+type imageText struct {
+	x, y       int
+	text       string
+	size       float64
+	bold       bool
+	rightAlign bool
+	color      color.Color
+}
+
+type imageCanvas struct {
+	width, height int
+	background    color.Color
+	rects         []imageRect
+	texts         []imageText
+}
+
+func (d *Day) WriteSVG(w io.Writer, opts *ImageOptions) error {
+	o, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	c, err := newDaysCanvas([]*Day{d}, o)
+	if err != nil {
+		return err
+	}
+
+	return c.writeSVG(w)
+}
+
+func (d *Day) WritePNG(w io.Writer, opts *ImageOptions) error {
+	o, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	c, err := newDaysCanvas([]*Day{d}, o)
+	if err != nil {
+		return err
+	}
+
+	return c.writePNG(w)
+}
+
+func (w *Week) WriteSVG(wr io.Writer, opts *ImageOptions) error {
+	o, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	days, err := w.GetDays()
+	if err != nil {
+		return err
+	}
+
+	c, err := newDaysCanvas(days, o)
+	if err != nil {
+		return err
+	}
+
+	return c.writeSVG(wr)
+}
+
+func (w *Week) WritePNG(wr io.Writer, opts *ImageOptions) error {
+	o, err := opts.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	days, err := w.GetDays()
+	if err != nil {
+		return err
+	}
+
+	c, err := newDaysCanvas(days, o)
+	if err != nil {
+		return err
+	}
+
+	return c.writePNG(wr)
+}
+
+func newDaysCanvas(days []*Day, opts ImageOptions) (*imageCanvas, error) {
+	if len(days) == 0 {
+		return nil, ErrInvalidDayData
+	}
+
+	faces := make(imageFaces)
+
+	c := new(imageCanvas)
+	c.width = opts.Width
+	c.background = opts.Theme.Background
+
+	size := opts.FontSize
+	lineHeight := int(size * 1.5)
+	colWidth := (opts.Width - imageMargin*(len(days)+1)) / len(days)
+
+	bottom := 0
+	for i, d := range days {
+		x := imageMargin + i*(colWidth+imageMargin)
+		y := imageMargin + int(size*1.4)
+
+		c.texts = append(c.texts, imageText{
+			x: x, y: y, text: formatDate(d.Start), size: size * 1.4, bold: true, color: opts.Theme.Foreground,
+		})
+		y += lineHeight
+
+		for j, cat := range d.OrderedCategories() {
+			t := cat.Label
+			catColor := opts.Theme.categoryColor(cat, j)
+			c.rects = append(c.rects, imageRect{x: x, y: y, w: colWidth, h: lineHeight, color: catColor})
+
+			label, err := faces.fit(strings.ToUpper(t), size*0.8, true, colWidth-2*imagePadding)
+			if err != nil {
+				return nil, err
+			}
+			c.texts = append(c.texts, imageText{
+				x: x + imagePadding, y: y + int(float64(lineHeight)*0.7), text: label, size: size * 0.8, bold: true, color: opts.Theme.Header,
+			})
+			y += lineHeight + imagePadding/2
+
+			for _, m := range d.Meals[t] {
+				nameWidth := colWidth - imagePadding
+				if price := m.PriceText(); price != "" {
+					priceWidth, err := faces.measure(price, size, false)
+					if err != nil {
+						return nil, err
+					}
+
+					nameWidth -= priceWidth + imagePadding
+					c.texts = append(c.texts, imageText{
+						x: x + colWidth, y: y + int(size), text: price, size: size, rightAlign: true, color: opts.Theme.Muted,
+					})
+				}
+
+				nameX := x + imagePadding
+				if m.Carbon != nil {
+					badge := int(size * 0.6)
+					c.rects = append(c.rects, imageRect{
+						x: nameX, y: y + int(size) - badge, w: badge, h: badge, color: carbonLevelColors[m.Carbon.Level],
+					})
+					nameX += badge + imagePadding
+					nameWidth -= badge + imagePadding
+				}
+
+				name, err := faces.fit(m.Name, size, false, nameWidth)
+				if err != nil {
+					return nil, err
+				}
+				c.texts = append(c.texts, imageText{
+					x: nameX, y: y + int(size), text: name, size: size, color: opts.Theme.Foreground,
+				})
+				y += lineHeight
+			}
+
+			y += imagePadding
+		}
+
+		if y > bottom {
+			bottom = y
+		}
+	}
+
+	if opts.Height > 0 {
+		c.height = opts.Height
+	} else {
+		// Long menus are cropped rather than allocating an unbounded image.
+		c.height = bottom + imageMargin
+		if c.height > MaxImageSize {
+			c.height = MaxImageSize
+		}
+	}
+
+	return c, nil
+}
+
+func (c *imageCanvas) writePNG(w io.Writer) error {
+	img, err := c.rasterize()
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
+
+func (c *imageCanvas) rasterize() (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, c.width, c.height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c.background), image.Point{}, draw.Src)
+
+	faces := make(imageFaces)
+
+	for _, r := range c.rects {
+		draw.Draw(img, image.Rect(r.x, r.y, r.x+r.w, r.y+r.h), image.NewUniform(r.color), image.Point{}, draw.Src)
+	}
+
+	for _, t := range c.texts {
+		face, err := faces.face(t.bold, t.size)
+		if err != nil {
+			return nil, err
+		}
+
+		drawer := font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(t.color),
+			Face: face,
+		}
+
+		x := fixed.I(t.x)
+		if t.rightAlign {
+			x -= drawer.MeasureString(t.text)
+		}
+		drawer.Dot = fixed.Point26_6{X: x, Y: fixed.I(t.y)}
+		drawer.DrawString(t.text)
+	}
+
+	return img, nil
+}
+
+func (c *imageCanvas) writeSVG(w io.Writer) error {
+	var b strings.Builder
+
+	_, _ = fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", c.width, c.height, c.width, c.height)
+	_, _ = fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(c.background))
+
+	for _, r := range c.rects {
+		_, _ = fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", r.x, r.y, r.w, r.h, svgColor(r.color))
+	}
+
+	for _, t := range c.texts {
+		weight, anchor := "normal", "start"
+		if t.bold {
+			weight = "bold"
+		}
+		if t.rightAlign {
+			anchor = "end"
+		}
+
+		_, _ = fmt.Fprintf(&b, `<text x="%d" y="%d" font-family="%s" font-size="%.1f" font-weight="%s" text-anchor="%s" fill="%s">`,
+			t.x, t.y, svgFontStack, t.size, weight, anchor, svgColor(t.color))
+		_ = xml.EscapeText(&b, []byte(t.text))
+		b.WriteString("</text>\n")
+	}
+
+	b.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func loadFonts() error {
+	fontsOnce.Do(func() {
+		regularFont, fontsErr = opentype.Parse(goregular.TTF)
+		if fontsErr != nil {
+			return
+		}
+
+		boldFont, fontsErr = opentype.Parse(gobold.TTF)
+	})
+
+	return fontsErr
+}
+
+func (fs imageFaces) face(bold bool, size float64) (font.Face, error) {
+	key := imageFaceKey{bold, size}
+	if face, pres := fs[key]; pres {
+		return face, nil
+	}
+
+	err := loadFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	f := regularFont
+	if bold {
+		f = boldFont
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fs[key] = face
+	return face, nil
+}
+
+func (fs imageFaces) measure(s string, size float64, bold bool) (int, error) {
+	face, err := fs.face(bold, size)
+	if err != nil {
+		return 0, err
+	}
+
+	return font.MeasureString(face, s).Ceil(), nil
+}
+
+func (fs imageFaces) fit(s string, size float64, bold bool, width int) (string, error) {
+	w, err := fs.measure(s, size, bold)
+	if err != nil || w <= width {
+		return s, err
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+
+		w, err := fs.measure(candidate, size, bold)
+		if err != nil {
+			return "", err
+		}
+		if w <= width {
+			return candidate, nil
+		}
+	}
+
+	return "", nil
+}
+
+func svgColor(c color.Color) string {
+	r, g, b, a := c.RGBA()
+	if a == 0xffff {
+		return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	}
+
+	return fmt.Sprintf("rgba(%d,%d,%d,%.3f)", r>>8, g>>8, b>>8, float64(a)/0xffff)
+}
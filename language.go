@@ -0,0 +1,266 @@
+package gorldline
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var (
+	French = newLanguage(
+		"fr", "Français",
+		[7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		[12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		"%[1]s %[2]d %[3]s", "%[1]s %[2]s", ",", "et",
+		func(n int) int {
+			if n <= 1 {
+				return 0
+			}
+			return 1
+		},
+		`{{if .Today}}Aujourd'hui{{else}}Le {{date .Date}}{{end}},
+		{{- if .Sections}} {{len .Sections}} {{plural (len .Sections) "stand est ouvert" "stands sont ouverts"}}.
+		{{- range .Sections}} Au stand {{.Type}}, {{plural (len .Names) "le plat proposé est" "les plats proposés sont"}} {{join .Names}}.{{end}}
+		{{- else}} aucun plat n'est proposé.{{end}}`,
+		`Du {{date .Start}} au {{date .End}}, {{len .Days}} {{plural (len .Days) "jour" "jours"}} de menu.
+		{{- range .Days}} {{.}}{{end}}`,
+	)
+	English = newLanguage(
+		"en", "English",
+		[7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		[12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		"%[1]s %[2]d %[3]s", "%[2]s%[1]s", ".", "and",
+		func(n int) int {
+			if n == 1 {
+				return 0
+			}
+			return 1
+		},
+		`{{if .Today}}Today{{else}}On {{date .Date}}{{end}},
+		{{- if .Sections}} {{len .Sections}} {{plural (len .Sections) "stand is" "stands are"}} open.
+		{{- range .Sections}} At the {{.Type}} stand, {{plural (len .Names) "the dish on offer is" "the dishes on offer are"}} {{join .Names}}.{{end}}
+		{{- else}} no dish is on offer.{{end}}`,
+		`From {{date .Start}} to {{date .End}}, {{len .Days}} {{plural (len .Days) "day" "days"}} of menus.
+		{{- range .Days}} {{.}}{{end}}`,
+	)
+	German = newLanguage(
+		"de", "Deutsch",
+		[7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		[12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		"%[1]s, %[2]d. %[3]s", "%[1]s %[2]s", ",", "und",
+		func(n int) int {
+			if n == 1 {
+				return 0
+			}
+			return 1
+		},
+		`{{if .Today}}Heute{{else}}Am {{date .Date}}{{end}}
+		{{- if .Sections}} {{plural (len .Sections) "ist" "sind"}} {{len .Sections}} {{plural (len .Sections) "Stand" "Stände"}} geöffnet.
+		{{- range .Sections}} Am Stand {{.Type}} gibt es {{join .Names}}.{{end}}
+		{{- else}} wird kein Gericht angeboten.{{end}}`,
+		`Vom {{date .Start}} bis {{date .End}}, {{len .Days}} {{plural (len .Days) "Tag" "Tage"}} mit Menü.
+		{{- range .Days}} {{.}}{{end}}`,
+	)
+	Spanish = newLanguage(
+		"es", "Español",
+		[7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		[12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		"%[1]s %[2]d de %[3]s", "%[1]s %[2]s", ",", "y",
+		func(n int) int {
+			if n == 1 {
+				return 0
+			}
+			return 1
+		},
+		`{{if .Today}}Hoy{{else}}El {{date .Date}}{{end}},
+		{{- if .Sections}} {{plural (len .Sections) "está abierto" "están abiertos"}} {{len .Sections}} {{plural (len .Sections) "puesto" "puestos"}}.
+		{{- range .Sections}} En el puesto {{.Type}}, {{plural (len .Names) "el plato del día es" "los platos del día son"}} {{join .Names}}.{{end}}
+		{{- else}} no se ofrece ningún plato.{{end}}`,
+		`Del {{date .Start}} al {{date .End}}, {{len .Days}} {{plural (len .Days) "día" "días"}} de menú.
+		{{- range .Days}} {{.}}{{end}}`,
+	)
+
+	Languages = []*Language{French, English, German, Spanish}
+)
+
+type Language struct {
+	Code string
+	Name string
+
+	weekdays    [7]string
+	months      [12]string
+	dateFormat  string
+	priceFormat string
+	decimal     string
+	and         string
+	plural      func(n int) int
+	daySummary  *template.Template
+	weekSummary *template.Template
+}
+
+func newLanguage(code, name string, weekdays [7]string, months [12]string, dateFormat, priceFormat, decimal, and string, plural func(int) int, day, week string) *Language {
+	l := new(Language)
+	l.Code = code
+	l.Name = name
+	l.weekdays = weekdays
+	l.months = months
+	l.dateFormat = dateFormat
+	l.priceFormat = priceFormat
+	l.decimal = decimal
+	l.and = and
+	l.plural = plural
+
+	funcs := template.FuncMap{
+		"date":   l.FormatDate,
+		"join":   l.Join,
+		"plural": l.Plural,
+	}
+	l.daySummary = template.Must(template.New(code + "-day").Funcs(funcs).Parse(day))
+	l.weekSummary = template.Must(template.New(code + "-week").Funcs(funcs).Parse(week))
+
+	return l
+}
+
+func (l *Language) FormatDate(t time.Time) string {
+	return fmt.Sprintf(l.dateFormat, l.weekdays[t.Weekday()], t.Day(), l.months[t.Month()-1])
+}
+
+func (l *Language) Join(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + " " + l.and + " " + items[len(items)-1]
+	}
+}
+
+func (l *Language) Plural(n int, forms ...string) string {
+	if len(forms) == 0 {
+		return ""
+	}
+
+	i := l.plural(n)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+
+	return forms[i]
+}
+
+func LanguageByCode(code string) *Language {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i != -1 {
+		code = code[:i]
+	}
+
+	for _, l := range Languages {
+		if l.Code == code {
+			return l
+		}
+	}
+
+	return nil
+}
+
+func MatchLanguage(acceptLanguage string, fallback *Language) *Language {
+	type candidate struct {
+		lang    *Language
+		quality float64
+	}
+
+	candidates := make([]candidate, 0)
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if l := LanguageByCode(fields[0]); l != nil && quality > 0 {
+			candidates = append(candidates, candidate{l, quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return fallback
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	return candidates[0].lang
+}
+
+type summarySection struct {
+	Type  string
+	Names []string
+}
+
+func (d *Day) Summary(l *Language, today bool) string {
+	data := struct {
+		Today    bool
+		Date     time.Time
+		Sections []summarySection
+	}{
+		Today: today,
+		Date:  d.Start,
+	}
+
+	for _, c := range d.OrderedCategories() {
+		names := make([]string, 0, len(d.Meals[c.Label]))
+		for _, m := range d.Meals[c.Label] {
+			names = append(names, m.Name)
+		}
+
+		if len(names) > 0 {
+			data.Sections = append(data.Sections, summarySection{c.Name(l), names})
+		}
+	}
+
+	return executeSummary(l.daySummary, data)
+}
+
+func (w *Week) Summary(l *Language) (string, error) {
+	days, err := w.GetDays()
+	if err != nil {
+		return "", err
+	}
+
+	data := struct {
+		Start time.Time
+		End   time.Time
+		Days  []string
+	}{
+		Start: w.Start,
+		End:   w.End,
+		Days:  make([]string, 0, len(days)),
+	}
+
+	for _, d := range days {
+		data.Days = append(data.Days, d.Summary(l, false))
+	}
+
+	return executeSummary(l.weekSummary, data), nil
+}
+
+func executeSummary(t *template.Template, data interface{}) string {
+	var b strings.Builder
+	err := t.Execute(&b, data)
+	if err != nil {
+		return ""
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -134,6 +134,45 @@ func (l *List) Nearest() *Week {
 	return nil
 }
 
+func (l *List) Find(id string) *Week {
+	for _, week := range l.Weeks {
+		if week.ID() == id {
+			return week
+		}
+	}
+
+	return nil
+}
+
+func (l *List) Day(t time.Time) (*Day, error) {
+	for _, week := range l.Weeks {
+		if t.Before(week.Start) || t.After(week.End) {
+			continue
+		}
+
+		return week.Day(t)
+	}
+
+	return nil, nil
+}
+
+func (l *List) Meal(id string) (*Meal, error) {
+	for _, week := range l.Weeks {
+		days, err := week.GetDays()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range days {
+			if m := d.Meal(id); m != nil {
+				return m, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
 func (l *List) Merge(other *List) {
 	newWeek := make([]*Week, 0)
 
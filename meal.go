@@ -1,6 +1,142 @@
 package gorldline
 
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+const (
+	mealIdLength = 12
+)
+
 type Meal struct {
-	Name  string `json:"name"`
-	Price int    `json:"price"`
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	Category     string             `json:"category"`
+	Date         time.Time          `json:"date"`
+	Price        Price              `json:"-"`
+	Priced       bool               `json:"priced"`
+	RawPrice     string             `json:"rawPrice,omitempty"`
+	PriceCode    *PriceCode         `json:"priceCode,omitempty"`
+	Tags         Tags               `json:"tags"`
+	Components   *Components        `json:"components"`
+	Allergens    []*AllergenMatch   `json:"allergens"`
+	Diets        []string           `json:"diets"`
+	Nutrition    *NutritionEstimate `json:"nutrition,omitempty"`
+	Carbon       *CarbonEstimate    `json:"carbon,omitempty"`
+	Rating       *RatingSummary     `json:"rating,omitempty"`
+	Translations map[string]string  `json:"translations,omitempty"`
+}
+
+func (m *Meal) PriceText() string {
+	switch {
+	case m.Priced && m.PriceCode != nil:
+		return m.Price.String() + " (" + m.PriceCode.Code + ")"
+	case m.Priced:
+		return m.Price.String()
+	case m.PriceCode != nil:
+		return m.PriceCode.String()
+	default:
+		return ""
+	}
+}
+
+type mealJson Meal
+
+// The price keeps its historical encoding, an amount in cents next to its currency, and is null when unknown.
+func (m *Meal) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		*mealJson
+		Price    *int   `json:"price"`
+		Currency string `json:"currency,omitempty"`
+	}{mealJson: (*mealJson)(m)}
+
+	if m.Priced {
+		aux.Price = &m.Price.Amount
+		aux.Currency = m.Price.Currency
+	}
+
+	return json.Marshal(aux)
+}
+
+func (m *Meal) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*mealJson
+		Price    *int   `json:"price"`
+		Currency string `json:"currency"`
+	}{mealJson: (*mealJson)(m)}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	m.Price = Price{}
+	if aux.Price != nil {
+		m.Price = Euros(*aux.Price)
+		if aux.Currency != "" {
+			m.Price.Currency = aux.Currency
+		}
+	}
+
+	return nil
+}
+
+func (m *Meal) identify(category *Category, date time.Time) {
+	m.Category = category.ID
+	m.Date = midnight(date)
+
+	sum := sha1.Sum([]byte(m.Date.Format("2006-01-02") + "|" + m.Category + "|" + categoryKey(m.Name)))
+	m.ID = hex.EncodeToString(sum[:])[:mealIdLength]
+
+	if m.Tags == nil {
+		m.Tags = make(Tags)
+	}
+}
+
+type Tags map[string]struct{}
+
+func (t Tags) Add(tag string) {
+	t[tag] = struct{}{}
+}
+
+func (t Tags) Remove(tag string) {
+	delete(t, tag)
+}
+
+func (t Tags) Has(tag string) bool {
+	_, pres := t[tag]
+	return pres
+}
+
+func (t Tags) List() []string {
+	tags := make([]string, 0, len(t))
+	for tag := range t {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
+func (t Tags) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.List())
+}
+
+func (t *Tags) UnmarshalJSON(data []byte) error {
+	var tags []string
+	err := json.Unmarshal(data, &tags)
+	if err != nil {
+		return err
+	}
+
+	*t = make(Tags, len(tags))
+	for _, tag := range tags {
+		t.Add(tag)
+	}
+
+	return nil
 }
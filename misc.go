@@ -1,19 +1,27 @@
 package gorldline
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 var (
 	ErrCannotParseDay = errors.New("error while parsing menu day string")
 )
 
+const (
+	displayDateFormat = "02/01/2006"
+)
+
 var (
 	dateLabelRegex = regexp.MustCompile(`(?mi)(\d+)\s*au\s*(\d+)\s*(\w+)`)
 
@@ -31,18 +39,43 @@ var (
 		"novembre",
 		"decembre",
 	}
+
+	// Function words and fillers ignored by every tokeniser, compared without accents.
+	frenchStopWords = []string{
+		"a", "au", "aux", "d", "de", "des", "du", "en", "et", "l", "la", "le", "les", "sa", "ses", "son", "sur",
+		"avec", "ou", "maison", "facon", "jour",
+	}
+
+	// Words that the stemmer would confuse once their accents are folded, "pâté" is not "pâtes".
+	stemExceptions = map[string]string{
+		"pâté": "pâté", "pâtés": "pâté",
+	}
+
+	// Words ending with "s" or "x" in the singular.
+	invariableWords = []string{
+		"anchois", "ananas", "brebis", "cassis", "couscous", "frais", "jus", "mais", "pois", "radis", "riz",
+		"tapas", "travers", "panais", "noix", "croque", "gras", "sans", "coulis",
+	}
+
 	locale   *time.Location
 	timeZero = time.Time{}
-)
 
-var (
-	dict = map[string]string{
-		" a ":       " à ",
-		"Peche":     "Pêche",
-		"nee":       "née",
-		"Burger us": "Burger US",
-		//"Bar a Legumes": "Bar à Legumes",
-	}
+	accentsReplacer = strings.NewReplacer(
+		"à", "a", "â", "a", "ä", "a", "á", "a",
+		"ç", "c",
+		"é", "e", "è", "e", "ê", "e", "ë", "e",
+		"î", "i", "ï", "i", "í", "i",
+		"ô", "o", "ö", "o", "ó", "o",
+		"ù", "u", "û", "u", "ü", "u", "ú", "u",
+		"ÿ", "y", "ñ", "n", "œ", "oe", "æ", "ae",
+		"À", "A", "Â", "A", "Ä", "A", "Á", "A",
+		"Ç", "C",
+		"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+		"Î", "I", "Ï", "I", "Í", "I",
+		"Ô", "O", "Ö", "O", "Ó", "O",
+		"Ù", "U", "Û", "U", "Ü", "U", "Ú", "U",
+		"Ÿ", "Y", "Ñ", "N", "Œ", "OE", "Æ", "AE",
+	)
 )
 
 func init() {
@@ -112,21 +145,8 @@ func parseDate(dateString string) (time.Time, time.Time, error) {
 	return start, end, nil
 }
 
-func parsePrice(s string) int {
-	s = strings.TrimSpace(s)
-	if s == "" || s == "CJ" {
-		return -1
-	}
-
-	s = strings.ReplaceAll(s, ".", "")
-	s = strings.ReplaceAll(s, ",", "")
-
-	v, err := strconv.Atoi(s)
-	if err != nil {
-		return -1
-	}
-
-	return v
+func formatDate(t time.Time) string {
+	return t.Format(displayDateFormat)
 }
 
 func isRowEmpty(row []string) bool {
@@ -165,9 +185,114 @@ func endOfDay(t time.Time) time.Time {
 	return midnight(t).Add(time.Hour*24 - time.Nanosecond)
 }
 
+func foldAccents(s string) string {
+	return accentsReplacer.Replace(s)
+}
+
+func isStopWord(word string) bool {
+	return containsString(frenchStopWords, strings.ToLower(foldAccents(word)))
+}
+
+// Plural endings are removed from a lowercase word, accents are kept.
+func singularFrench(word string) string {
+	folded := foldAccents(word)
+	if containsString(invariableWords, folded) {
+		return word
+	}
+
+	switch {
+	case len(folded) > 5 && strings.HasSuffix(folded, "aux") && !strings.HasSuffix(folded, "eaux"):
+		return strings.TrimSuffix(word, "ux") + "l"
+	case len(folded) > 3 && (strings.HasSuffix(folded, "s") || strings.HasSuffix(folded, "x")):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+// A light French stemmer: plural and feminine endings are removed, which is enough for dish names.
+func StemFrench(word string) string {
+	word = strings.ToLower(word)
+	if stem, pres := stemExceptions[word]; pres {
+		return stem
+	}
+
+	word = foldAccents(word)
+	if containsString(invariableWords, word) {
+		return word
+	}
+
+	word = singularFrench(word)
+	for len(word) > 4 && strings.HasSuffix(word, "e") {
+		word = word[:len(word)-1]
+	}
+
+	return word
+}
+
+func termWords(s string) []string {
+	words := wordRegex.FindAllString(s, -1)
+	for i, w := range words {
+		words[i] = StemFrench(w)
+	}
+
+	return words
+}
+
+// The stems of the words of s that are not stop words.
+func meaningfulWords(s string) []string {
+	words := make([]string, 0)
+	for _, w := range wordRegex.FindAllString(s, -1) {
+		if !isStopWord(w) {
+			words = append(words, StemFrench(w))
+		}
+	}
+
+	return words
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
 func smoothGrammar(s string) string {
-	for k, v := range dict {
-		s = strings.ReplaceAll(s, k, v)
+	return DefaultNormalizer.Normalize(s)
+}
+
+// The file is replaced atomically so that a crash never leaves half-written data behind.
+func writeJsonFile(path string, v interface{}) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
 	}
-	return s
+
+	err = json.NewEncoder(tmp).Encode(v)
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
 }
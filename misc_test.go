@@ -0,0 +1,48 @@
+package gorldline
+
+import (
+	"testing"
+)
+
+func TestStemFrench(t *testing.T) {
+	cases := map[string]string{
+		"Carottes":   "carott",
+		"carotte":    "carott",
+		"Épicée":     "epic",
+		"épicé":      "epic",
+		"Chevaux":    "cheval",
+		"Poireaux":   "poireau",
+		"Pâtes":      "pate",
+		"pâte":       "pate",
+		"pates":      "pate",
+		"Pâté":       "pâté",
+		"pâtés":      "pâté",
+		"Anchois":    "anchois",
+		"Croque":     "croque",
+		"Noix":       "noix",
+		"Riz":        "riz",
+		"sans":       "sans",
+		"Boulettes":  "boulett",
+		"Lasagnes":   "lasagn",
+		"Cannelloni": "cannelloni",
+	}
+
+	for in, want := range cases {
+		if got := StemFrench(in); got != want {
+			t.Errorf("StemFrench(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMeaningfulWords(t *testing.T) {
+	got := meaningfulWords("Filet de colin à la crème, riz du jour")
+	want := []string{"filet", "colin", "crem", "riz"}
+	if len(got) != len(want) {
+		t.Fatalf("meaningfulWords = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("meaningfulWords = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package gorldline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	RuleWord         = "word"
+	RuleAccent       = "accent"
+	RuleAbbreviation = "abbreviation"
+	RuleSuffix       = "suffix"
+	RuleCase         = "case"
+	RuleWhitespace   = "whitespace"
+	RuleDictionary   = "dictionary"
+
+	CaseCapitalize = "capitalize"
+	CaseSentence   = "sentence"
+)
+
+var (
+	ErrInvalidNormalizationFile = errors.New("invalid normalization rules file")
+	ErrUnknownNormalizationRule = errors.New("unknown normalization rule type")
+)
+
+var (
+	DefaultNormalizer = NewNormalizer(
+		WhitespaceRule{},
+		&ReplaceRule{Kind: RuleAbbreviation, From: "pdt", To: "pommes de terre", IgnoreCase: true},
+		&ReplaceRule{Kind: RuleWord, From: "a", To: "à"},
+		DefaultAccentRestorer,
+		&ReplaceRule{Kind: RuleSuffix, From: "nee", To: "née"},
+		&ReplaceRule{Kind: RuleWord, From: "Burger us", To: "Burger US", IgnoreCase: true},
+		CaseRule{Mode: CaseCapitalize},
+	)
+
+	spacesRegex           = regexp.MustCompile(`\s+`)
+	spaceBeforePunctRegex = regexp.MustCompile(`\s+([,.)])`)
+	spaceAfterParenRegex  = regexp.MustCompile(`\(\s+`)
+	// A comma between two digits is a French decimal separator and is left alone.
+	spaceAfterCommaRegex      = regexp.MustCompile(`,([\p{L}(])`)
+	spaceAfterCommaDigitRegex = regexp.MustCompile(`([^\p{N}\s]),(\p{N})`)
+	spaceAfterCloseParenRegex = regexp.MustCompile(`\)([\p{L}\p{N}(])`)
+)
+
+type NormalizationRule interface {
+	Name() string
+	Apply(s string) string
+}
+
+type RuleApplication struct {
+	Rule   string `json:"rule"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+func NewNormalizer(rules ...NormalizationRule) *Normalizer {
+	n := new(Normalizer)
+	n.Rules = rules
+
+	return n
+}
+
+type Normalizer struct {
+	Rules []NormalizationRule
+}
+
+func (n *Normalizer) Normalize(s string) string {
+	for _, r := range n.Rules {
+		s = r.Apply(s)
+	}
+
+	return s
+}
+
+func (n *Normalizer) Explain(s string) (string, []RuleApplication) {
+	fired := make([]RuleApplication, 0)
+	for _, r := range n.Rules {
+		after := r.Apply(s)
+		if after != s {
+			fired = append(fired, RuleApplication{r.Name(), s, after})
+		}
+		s = after
+	}
+
+	return s, fired
+}
+
+type ReplaceRule struct {
+	Kind       string
+	From       string
+	To         string
+	IgnoreCase bool
+}
+
+func (r *ReplaceRule) Name() string {
+	return fmt.Sprintf("%s(%s → %s)", r.Kind, r.From, r.To)
+}
+
+func (r *ReplaceRule) Apply(s string) string {
+	if r.From == "" {
+		return s
+	}
+
+	haystack, needle := s, r.From
+	if r.IgnoreCase {
+		haystack, needle = strings.ToLower(s), strings.ToLower(r.From)
+		if len(haystack) != len(s) {
+			haystack, needle = s, r.From
+		}
+	}
+
+	var b strings.Builder
+	last, offset := 0, 0
+	for {
+		i := strings.Index(haystack[offset:], needle)
+		if i == -1 {
+			break
+		}
+
+		start, end := offset+i, offset+i+len(needle)
+		offset = end
+
+		if !isWordBoundary(s, end, true) || (r.Kind != RuleSuffix && !isWordBoundary(s, start, false)) {
+			offset = start + 1
+			continue
+		}
+
+		b.WriteString(s[last:start])
+		if r.IgnoreCase {
+			b.WriteString(matchWordCase(s[start:end], r.To))
+		} else {
+			b.WriteString(r.To)
+		}
+		last = end
+	}
+
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+type CaseRule struct {
+	Mode string
+}
+
+func (r CaseRule) Name() string {
+	return fmt.Sprintf("%s(%s)", RuleCase, r.Mode)
+}
+
+func (r CaseRule) Apply(s string) string {
+	switch r.Mode {
+	case CaseSentence:
+		if strings.ToUpper(s) != s || strings.ToLower(s) == s {
+			return s
+		}
+		return capitalize(strings.ToLower(s))
+	case CaseCapitalize:
+		return capitalize(s)
+	default:
+		return s
+	}
+}
+
+type WhitespaceRule struct{}
+
+func (r WhitespaceRule) Name() string {
+	return RuleWhitespace
+}
+
+func (r WhitespaceRule) Apply(s string) string {
+	s = spacesRegex.ReplaceAllString(s, " ")
+	s = spaceBeforePunctRegex.ReplaceAllString(s, "$1")
+	s = spaceAfterCommaRegex.ReplaceAllString(s, ", $1")
+	s = spaceAfterCommaDigitRegex.ReplaceAllString(s, "$1, $2")
+	s = spaceAfterCloseParenRegex.ReplaceAllString(s, ") $1")
+	s = spaceAfterParenRegex.ReplaceAllString(s, "(")
+	return strings.TrimSpace(s)
+}
+
+type normalizationRuleFile struct {
+	Type       string            `json:"type"`
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Words      map[string]string `json:"words"`
+	IgnoreCase bool              `json:"ignoreCase"`
+	Mode       string            `json:"mode"`
+}
+
+func LoadNormalizerFile(path string) (*Normalizer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var entries []normalizationRuleFile
+	err = json.NewDecoder(file).Decode(&entries)
+	if err != nil {
+		return nil, ErrInvalidNormalizationFile
+	}
+
+	rules := make([]NormalizationRule, 0, len(entries))
+	for _, e := range entries {
+		switch e.Type {
+		case RuleWord, RuleAccent, RuleAbbreviation, RuleSuffix:
+			if e.From != "" {
+				rules = append(rules, &ReplaceRule{e.Type, e.From, e.To, e.IgnoreCase})
+			}
+			// Longer words first so that overlapping entries are applied deterministically.
+			words := make([]string, 0, len(e.Words))
+			for from := range e.Words {
+				words = append(words, from)
+			}
+			sort.Slice(words, func(i, j int) bool {
+				if len(words[i]) != len(words[j]) {
+					return len(words[i]) > len(words[j])
+				}
+				return words[i] < words[j]
+			})
+			for _, from := range words {
+				rules = append(rules, &ReplaceRule{e.Type, from, e.Words[from], e.IgnoreCase})
+			}
+		case RuleDictionary:
+			rules = append(rules, DefaultAccentRestorer)
+		case RuleCase:
+			rules = append(rules, CaseRule{e.Mode})
+		case RuleWhitespace:
+			rules = append(rules, WhitespaceRule{})
+		default:
+			return nil, ErrUnknownNormalizationRule
+		}
+	}
+
+	return NewNormalizer(rules...), nil
+}
+
+func isWordBoundary(s string, i int, after bool) bool {
+	var r rune
+	if after {
+		if i >= len(s) {
+			return true
+		}
+		r, _ = utf8.DecodeRuneInString(s[i:])
+	} else {
+		if i <= 0 {
+			return true
+		}
+		r, _ = utf8.DecodeLastRuneInString(s[:i])
+	}
+
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func matchWordCase(original, replacement string) string {
+	if utf8.RuneCountInString(original) > 1 && strings.ToUpper(original) == original && strings.ToLower(original) != original {
+		return strings.ToUpper(replacement)
+	}
+
+	r, _ := utf8.DecodeRuneInString(original)
+	if unicode.IsUpper(r) {
+		return capitalize(replacement)
+	}
+
+	first, size := utf8.DecodeRuneInString(replacement)
+	return string(unicode.ToLower(first)) + replacement[size:]
+}
@@ -0,0 +1,28 @@
+package gorldline
+
+import (
+	"testing"
+)
+
+func TestWhitespaceRuleFrenchDecimals(t *testing.T) {
+	cases := map[string]string{
+		"Yaourt 0,5%":            "Yaourt 0,5%",
+		"Lait 1,5 % mg":          "Lait 1,5 % mg",
+		"Poulet,frites":          "Poulet, frites",
+		"Frites,2 sauces":        "Frites, 2 sauces",
+		"Salade (tomates)olives": "Salade (tomates) olives",
+		"Steak ,( haché)":        "Steak, (haché)",
+	}
+
+	for in, want := range cases {
+		if got := (WhitespaceRule{}).Apply(in); got != want {
+			t.Errorf("Apply(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizerKeepsDecimals(t *testing.T) {
+	if got := DefaultNormalizer.Normalize("yaourt 0,5% mg"); got != "Yaourt 0,5% mg" {
+		t.Errorf("Normalize = %q", got)
+	}
+}
@@ -0,0 +1,193 @@
+package gorldline
+
+import (
+	"math"
+)
+
+var (
+	// Approximate values per 100g from the French CIQUAL food composition table, with a typical canteen portion.
+	ciqualFoods = []*Food{
+		// Meat and fish.
+		{"poulet roti", Nutrients{200, 27, 10, 0}, 120},
+		{"poulet", Nutrients{165, 26, 7, 0}, 120},
+		{"dinde", Nutrients{135, 29, 2, 0}, 120},
+		{"escalope", Nutrients{140, 28, 3, 0}, 120},
+		{"cordon bleu", Nutrients{240, 14, 13, 16}, 120},
+		{"canard", Nutrients{200, 25, 11, 0}, 120},
+		{"steak hache", Nutrients{250, 26, 16, 0}, 100},
+		{"steak", Nutrients{180, 28, 7, 0}, 120},
+		{"boeuf", Nutrients{190, 28, 8, 0}, 120},
+		{"boeuf bourguignon", Nutrients{140, 16, 7, 3}, 250},
+		{"blanquette de veau", Nutrients{130, 13, 7, 4}, 250},
+		{"veau", Nutrients{160, 28, 5, 0}, 120},
+		{"agneau", Nutrients{230, 25, 14, 0}, 120},
+		{"porc", Nutrients{210, 27, 11, 0}, 120},
+		{"roti de porc", Nutrients{190, 28, 8, 0}, 120},
+		{"saucisse", Nutrients{290, 14, 25, 1}, 120},
+		{"merguez", Nutrients{290, 15, 25, 1}, 120},
+		{"jambon", Nutrients{115, 20, 3.5, 1}, 80},
+		{"pâté", Nutrients{300, 15, 26, 2}, 50},
+		{"boulette", Nutrients{230, 15, 16, 6}, 150},
+		{"poisson", Nutrients{100, 22, 1, 0}, 150},
+		{"cabillaud", Nutrients{90, 20, 0.7, 0}, 150},
+		{"colin", Nutrients{95, 20, 1, 0}, 150},
+		{"merlu", Nutrients{95, 19, 1.5, 0}, 150},
+		{"saumon", Nutrients{200, 22, 12, 0}, 150},
+		{"thon", Nutrients{140, 28, 3, 0}, 120},
+		{"poisson pane", Nutrients{220, 12, 11, 18}, 150},
+		{"moules", Nutrients{120, 17, 3.5, 5}, 300},
+		{"oeuf", Nutrients{145, 13, 10, 0.7}, 100},
+		{"omelette", Nutrients{150, 11, 11, 1}, 150},
+		{"tofu", Nutrients{120, 12, 7, 2}, 150},
+		{"falafel", Nutrients{330, 13, 18, 31}, 120},
+		// Composed dishes.
+		{"hachis parmentier", Nutrients{130, 8, 6, 11}, 300},
+		{"lasagnes", Nutrients{150, 8, 7, 14}, 300},
+		{"quiche", Nutrients{270, 10, 18, 18}, 150},
+		{"pizza", Nutrients{250, 10, 9, 31}, 200},
+		{"croque monsieur", Nutrients{260, 13, 13, 22}, 150},
+		{"burger", Nutrients{250, 13, 12, 23}, 220},
+		{"choucroute", Nutrients{140, 8, 10, 3}, 350},
+		{"couscous", Nutrients{160, 10, 8, 13}, 350},
+		{"tartiflette", Nutrients{180, 7.5, 11, 13}, 300},
+		{"cassoulet", Nutrients{140, 9, 7, 10}, 300},
+		{"chili con carne", Nutrients{120, 9, 5, 10}, 300},
+		{"paella", Nutrients{150, 10, 5, 17}, 300},
+		{"gratin dauphinois", Nutrients{130, 3.5, 8, 11}, 200},
+		{"welsh", Nutrients{290, 14, 18, 17}, 350},
+		// Sides.
+		{"frites", Nutrients{270, 3.5, 13, 34}, 150},
+		{"potatoes", Nutrients{180, 2.5, 8, 24}, 150},
+		{"puree", Nutrients{90, 2, 3.5, 13}, 200},
+		{"pommes de terre", Nutrients{80, 2, 0.1, 17}, 200},
+		{"riz", Nutrients{145, 3, 0.5, 31}, 180},
+		{"pates", Nutrients{150, 5, 1, 30}, 200},
+		{"semoule", Nutrients{140, 5, 0.5, 29}, 180},
+		{"lentilles", Nutrients{115, 9, 0.5, 17}, 150},
+		{"haricots verts", Nutrients{30, 2, 0.2, 4}, 150},
+		{"legumes", Nutrients{60, 2, 3, 6}, 150},
+		{"ratatouille", Nutrients{60, 1.2, 4, 5}, 150},
+		{"carottes", Nutrients{30, 0.7, 0.2, 6}, 150},
+		{"epinards", Nutrients{30, 3, 0.5, 1.5}, 150},
+		{"brocolis", Nutrients{35, 3, 0.5, 3}, 150},
+		{"salade", Nutrients{15, 1.3, 0.2, 1.5}, 80},
+		{"crudites", Nutrients{40, 1, 2.5, 4}, 100},
+		{"potage", Nutrients{40, 1, 1.5, 5}, 250},
+		{"soupe", Nutrients{40, 1, 1.5, 5}, 250},
+		// Dairy and desserts.
+		{"fromage", Nutrients{350, 23, 28, 0.5}, 30},
+		{"yaourt", Nutrients{60, 4, 1.5, 7}, 125},
+		{"fromage blanc", Nutrients{75, 7, 3, 4}, 100},
+		{"tarte", Nutrients{240, 3, 11, 32}, 100},
+		{"gateau", Nutrients{380, 5, 18, 50}, 80},
+		{"mousse au chocolat", Nutrients{220, 5, 14, 19}, 100},
+		{"creme brulee", Nutrients{290, 4, 23, 17}, 100},
+		{"riz au lait", Nutrients{120, 3.5, 3, 20}, 125},
+		{"compote", Nutrients{70, 0.3, 0.1, 16}, 100},
+		{"fruit", Nutrients{50, 0.5, 0.2, 11}, 150},
+	}
+
+	DefaultNutritionEstimator = NewNutritionEstimator(ciqualFoods)
+)
+
+type Nutrients struct {
+	Energy  float64 `json:"kcal"`
+	Protein float64 `json:"protein"`
+	Fat     float64 `json:"fat"`
+	Carbs   float64 `json:"carbs"`
+}
+
+func (n Nutrients) add(other Nutrients, factor float64) Nutrients {
+	return Nutrients{
+		n.Energy + other.Energy*factor,
+		n.Protein + other.Protein*factor,
+		n.Fat + other.Fat*factor,
+		n.Carbs + other.Carbs*factor,
+	}
+}
+
+func (n Nutrients) rounded() Nutrients {
+	return Nutrients{math.Round(n.Energy), roundTenth(n.Protein), roundTenth(n.Fat), roundTenth(n.Carbs)}
+}
+
+type Food struct {
+	Name    string
+	Per100g Nutrients
+	Portion float64
+}
+
+type NutritionEstimate struct {
+	Nutrients
+	Confidence float64  `json:"confidence"`
+	Sources    []string `json:"sources"`
+}
+
+type NutritionEstimator struct {
+	foods map[string]*Food
+	terms *termMatcher
+}
+
+func NewNutritionEstimator(foods []*Food) *NutritionEstimator {
+	e := new(NutritionEstimator)
+	e.foods = make(map[string]*Food, len(foods))
+	e.terms = newTermMatcher()
+
+	for _, f := range foods {
+		e.AddFood(f)
+	}
+
+	return e
+}
+
+func (e *NutritionEstimator) AddFood(f *Food) {
+	e.foods[f.Name] = f
+	e.terms.add(f.Name, []string{f.Name})
+}
+
+// Each recognised food contributes one portion; the confidence is the share of meaningful words that were recognised.
+func (e *NutritionEstimator) Estimate(name string) *NutritionEstimate {
+	matches := e.terms.find(name)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	estimate := new(NutritionEstimate)
+	covered := 0
+	for _, m := range matches {
+		if m.negated {
+			continue
+		}
+
+		f := e.foods[m.values[0]]
+		estimate.Nutrients = estimate.Nutrients.add(f.Per100g, f.Portion/100)
+		estimate.Sources = append(estimate.Sources, f.Name)
+		covered += len(meaningfulWords(m.text))
+	}
+
+	if len(estimate.Sources) == 0 {
+		return nil
+	}
+
+	total := len(meaningfulWords(name))
+	estimate.Nutrients = estimate.Nutrients.rounded()
+	estimate.Confidence = 1
+	if total > covered {
+		estimate.Confidence = roundTenth(float64(covered) / float64(total))
+	}
+
+	return estimate
+}
+
+func (e *NutritionEstimator) EstimateDays(days []*Day) {
+	for _, d := range days {
+		for _, meals := range d.Meals {
+			for _, m := range meals {
+				m.Nutrition = e.Estimate(m.Name)
+			}
+		}
+	}
+}
+
+func roundTenth(f float64) float64 {
+	return math.Round(f*10) / 10
+}
@@ -0,0 +1,37 @@
+package gorldline
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNutritionEstimate(t *testing.T) {
+	cases := []struct {
+		name       string
+		energy     float64
+		confidence float64
+		sources    []string
+	}{
+		{"Poulet rôti, frites", 645, 1, []string{"poulet roti", "frites"}},
+		{"Boeuf bourguignon", 350, 1, []string{"boeuf bourguignon"}},
+		{"Pâté de campagne", 150, 0.5, []string{"pâté"}},
+		{"Pâtes carbonara", 300, 0.5, []string{"pates"}},
+		{"Lasagnes bolognaise", 450, 0.5, []string{"lasagnes"}},
+		{"Poulet sans frites", 198, 0.3, []string{"poulet"}},
+	}
+
+	for _, c := range cases {
+		e := DefaultNutritionEstimator.Estimate(c.name)
+		if e == nil {
+			t.Errorf("Estimate(%q) = nil", c.name)
+			continue
+		}
+		if e.Energy != c.energy || e.Confidence != c.confidence || !reflect.DeepEqual(e.Sources, c.sources) {
+			t.Errorf("Estimate(%q) = %v kcal, %v from %v, want %v kcal, %v from %v", c.name, e.Energy, e.Confidence, e.Sources, c.energy, c.confidence, c.sources)
+		}
+	}
+
+	if e := DefaultNutritionEstimator.Estimate("Surprise du chef"); e != nil {
+		t.Errorf("Estimate(%q) = %+v, want nil", "Surprise du chef", e)
+	}
+}
@@ -0,0 +1,146 @@
+package gorldline
+
+import (
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	pdfMargin        = 10.0
+	pdfTitleHeight   = 14.0
+	pdfHeaderHeight  = 8.0
+	pdfTypeWidth     = 36.0
+	pdfLineHeight    = 4.5
+	pdfCellPadding   = 1.5
+	pdfLogoMaxHeight = 14.0
+	pdfFont          = "Helvetica"
+)
+
+type PDFOptions struct {
+	LogoPath string
+}
+
+func (w *Week) WritePDF(wr io.Writer, opts *PDFOptions) error {
+	days, err := w.GetDays()
+	if err != nil {
+		return err
+	}
+
+	if len(days) == 0 {
+		return ErrInvalidSheetData
+	}
+
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle(w.title(), true)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.AddPage()
+
+	tr := pdf.UnicodeTranslatorFromDescriptor("cp1252")
+	pageWidth, pageHeight := pdf.GetPageSize()
+	contentWidth := pageWidth - 2*pdfMargin
+
+	if opts != nil && opts.LogoPath != "" {
+		pdf.ImageOptions(opts.LogoPath, pageWidth-pdfMargin-pdfLogoMaxHeight*3, pdfMargin, 0, pdfLogoMaxHeight, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+	}
+
+	pdf.SetFont(pdfFont, "B", 18)
+	pdf.SetXY(pdfMargin, pdfMargin)
+	pdf.CellFormat(contentWidth, pdfTitleHeight, tr(w.title()), "", 1, "L", false, 0, "")
+
+	types := weekTypes(days)
+	dayWidth := (contentWidth - pdfTypeWidth) / float64(len(days))
+	y := pdfMargin + pdfTitleHeight + 4
+
+	pdf.SetFillColor(0x00, 0x66, 0xa1)
+	pdf.SetTextColor(0xff, 0xff, 0xff)
+	pdf.SetDrawColor(0xbb, 0xbb, 0xbb)
+	pdf.SetFont(pdfFont, "B", 11)
+	pdf.SetXY(pdfMargin+pdfTypeWidth, y)
+	for _, d := range days {
+		pdf.CellFormat(dayWidth, pdfHeaderHeight, tr(formatDate(d.Start)), "1", 0, "C", true, 0, "")
+	}
+	y += pdfHeaderHeight
+
+	pdf.SetTextColor(0x22, 0x22, 0x22)
+	for i, t := range types {
+		pdf.SetFont(pdfFont, "", 9)
+
+		cells := make([][]string, len(days))
+		lines := 1
+		for j, d := range days {
+			for _, m := range d.Meals[t] {
+				text := m.Name
+				if price := m.PriceText(); price != "" {
+					text += " - " + price
+				}
+				if m.Carbon != nil {
+					text += " [CO2 " + m.Carbon.LevelName(French) + "]"
+				}
+
+				for _, l := range pdf.SplitLines([]byte(tr(text)), dayWidth-2*pdfCellPadding) {
+					cells[j] = append(cells[j], string(l))
+				}
+			}
+
+			if len(cells[j]) > lines {
+				lines = len(cells[j])
+			}
+		}
+
+		rowHeight := float64(lines)*pdfLineHeight + 2*pdfCellPadding
+		if y+rowHeight > pageHeight-pdfMargin {
+			pdf.AddPage()
+			y = pdfMargin
+		}
+
+		if i%2 == 0 {
+			pdf.SetFillColor(0xf4, 0xf4, 0xf4)
+		} else {
+			pdf.SetFillColor(0xff, 0xff, 0xff)
+		}
+
+		pdf.SetFont(pdfFont, "B", 9)
+		pdf.Rect(pdfMargin, y, pdfTypeWidth, rowHeight, "FD")
+		typeLines := pdf.SplitLines([]byte(tr(t)), pdfTypeWidth-2*pdfCellPadding)
+		for k, l := range typeLines {
+			pdf.SetXY(pdfMargin+pdfCellPadding, y+pdfCellPadding+float64(k)*pdfLineHeight)
+			pdf.CellFormat(pdfTypeWidth-2*pdfCellPadding, pdfLineHeight, string(l), "", 0, "L", false, 0, "")
+		}
+
+		pdf.SetFont(pdfFont, "", 9)
+		for j := range days {
+			x := pdfMargin + pdfTypeWidth + float64(j)*dayWidth
+			pdf.Rect(x, y, dayWidth, rowHeight, "FD")
+			for k, l := range cells[j] {
+				pdf.SetXY(x+pdfCellPadding, y+pdfCellPadding+float64(k)*pdfLineHeight)
+				pdf.CellFormat(dayWidth-2*pdfCellPadding, pdfLineHeight, l, "", 0, "L", false, 0, "")
+			}
+		}
+
+		y += rowHeight
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+
+	return pdf.Output(wr)
+}
+
+func weekTypes(days []*Day) []string {
+	union := make(map[string][]*Meal)
+	for _, d := range days {
+		for t := range d.Meals {
+			union[t] = nil
+		}
+	}
+
+	types := make([]string, 0, len(union))
+	for _, c := range categorize(union) {
+		types = append(types, c.Label)
+	}
+
+	return types
+}
@@ -0,0 +1,293 @@
+package gorldline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	likeScore       = 2
+	varietyPenalty  = 1
+	categoryPenalty = 0.5
+	// Ratings range from 1 to 5 and are centred on 3.
+	worstRatingScore = -2
+)
+
+type PlanPreferences struct {
+	Likes            []string `json:"likes"`
+	Dislikes         []string `json:"dislikes"`
+	Diet             string   `json:"diet"`
+	Without          []string `json:"without"`
+	Budget           *Price   `json:"budget"`
+	NoRepeatCategory bool     `json:"noRepeatCategory"`
+}
+
+type PlanChoice struct {
+	Date    time.Time `json:"date"`
+	Meal    *Meal     `json:"meal"`
+	Score   float64   `json:"score"`
+	Reasons []string  `json:"reasons"`
+}
+
+type Plan struct {
+	Choices []*PlanChoice `json:"choices"`
+	Total   Price         `json:"total"`
+	Budget  *Price        `json:"budget,omitempty"`
+	Score   float64       `json:"score"`
+	Notes   []string      `json:"notes"`
+}
+
+type planCandidate struct {
+	meal    *Meal
+	cost    int
+	score   float64
+	dish    string
+	reasons []string
+}
+
+type planSearch struct {
+	prefs      *PlanPreferences
+	candidates [][]*planCandidate
+	bounds     []float64
+	current    []*planCandidate
+	categories map[string]int
+	dishes     map[string]int
+	skip       float64
+	best       []*planCandidate
+	bestScore  float64
+	bestCost   int
+}
+
+func PlanWeek(w *Week, prefs *PlanPreferences) (*Plan, error) {
+	days, err := w.GetDays()
+	if err != nil {
+		return nil, err
+	}
+
+	return PlanDays(days, prefs), nil
+}
+
+// Every combination of one main dish per day is explored, days may be left empty when no dish fits the constraints.
+func PlanDays(days []*Day, prefs *PlanPreferences) *Plan {
+	if prefs == nil {
+		prefs = new(PlanPreferences)
+	}
+
+	s := new(planSearch)
+	s.prefs = prefs
+	s.categories = make(map[string]int)
+	s.dishes = make(map[string]int)
+	s.skip = skipPenalty(len(days))
+	s.bestScore = -s.skip*float64(len(days)) - 1
+
+	excluded := make(map[string]int)
+	for _, d := range days {
+		s.candidates = append(s.candidates, planCandidates(d, prefs, excluded))
+	}
+
+	s.bounds = make([]float64, len(days)+1)
+	for i := len(days) - 1; i >= 0; i-- {
+		best := -s.skip
+		for _, c := range s.candidates[i] {
+			if c.score > best {
+				best = c.score
+			}
+		}
+		s.bounds[i] = s.bounds[i+1] + best
+	}
+
+	s.search(0, 0, 0)
+
+	p := new(Plan)
+	p.Budget = prefs.Budget
+	p.Score = s.bestScore
+	p.Notes = make([]string, 0)
+
+	total := 0
+	for i, d := range days {
+		choice := &PlanChoice{Date: d.Start, Reasons: make([]string, 0)}
+		if c := s.best[i]; c != nil {
+			choice.Meal = c.meal
+			choice.Score = c.score
+			choice.Reasons = c.reasons
+			if prefs.Budget != nil && c.meal.Priced && isCheapest(c, s.candidates[i]) {
+				choice.Reasons = append(choice.Reasons, "moins cher du jour")
+			}
+			total += c.cost
+		} else if len(s.candidates[i]) == 0 {
+			choice.Reasons = append(choice.Reasons, "aucun plat compatible avec les préférences")
+		} else {
+			choice.Reasons = append(choice.Reasons, "plus de plat possible dans le budget ou sans répéter un stand")
+		}
+		p.Choices = append(p.Choices, choice)
+	}
+	p.Total = Euros(total)
+
+	if prefs.Budget != nil {
+		p.Notes = append(p.Notes, fmt.Sprintf("Budget : %s dépensés sur %s", p.Total, *prefs.Budget))
+	}
+	for _, reason := range sortedExclusions(excluded) {
+		n := excluded[reason]
+		p.Notes = append(p.Notes, fmt.Sprintf("%d %s (%s)", n, French.Plural(n, "plat écarté", "plats écartés"), reason))
+	}
+
+	return p
+}
+
+// Leaving a day empty must always cost more than the worst rating plus any combination of repeats.
+func skipPenalty(days int) float64 {
+	return -worstRatingScore + (varietyPenalty+categoryPenalty)*float64(days) + 1
+}
+
+func planCandidates(d *Day, prefs *PlanPreferences, excluded map[string]int) []*planCandidate {
+	candidates := make([]*planCandidate, 0)
+	for _, c := range d.OrderedCategories() {
+		if c.IsSide() {
+			continue
+		}
+
+		for _, m := range d.Meals[c.Label] {
+			if reason := planExclusion(m, prefs); reason != "" {
+				excluded[reason]++
+				continue
+			}
+			// The cost of an unpriced meal is unknown, so it cannot be counted against a budget.
+			if prefs.Budget != nil && !m.Priced {
+				excluded["prix non communiqué"]++
+				continue
+			}
+
+			candidate := &planCandidate{meal: m, reasons: make([]string, 0)}
+			for _, like := range prefs.Likes {
+				if MatchFavourite(like, m.Name) {
+					candidate.score += likeScore
+					candidate.reasons = append(candidate.reasons, fmt.Sprintf("aime « %s »", like))
+				}
+			}
+			if m.Rating != nil && m.Rating.Votes > 0 {
+				candidate.score += m.Rating.Average - 3
+				candidate.reasons = append(candidate.reasons, "noté "+m.Rating.String())
+			}
+			if m.Priced {
+				candidate.cost = m.Price.Amount
+			} else {
+				candidate.reasons = append(candidate.reasons, "prix non communiqué")
+			}
+			if len(candidate.reasons) == 0 {
+				candidate.reasons = append(candidate.reasons, "choisi pour varier la semaine")
+			}
+
+			candidate.dish = DishKey(m.Name)
+			if m.Components != nil && m.Components.Main != "" {
+				candidate.dish = DishKey(m.Components.Main)
+			}
+
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates
+}
+
+func planExclusion(m *Meal, prefs *PlanPreferences) string {
+	if prefs.Diet != "" && !m.HasDiet(prefs.Diet) {
+		return "régime " + prefs.Diet
+	}
+	for _, id := range prefs.Without {
+		if m.HasAllergen(id) {
+			return "allergène " + id
+		}
+	}
+	for _, dislike := range prefs.Dislikes {
+		if MatchFavourite(dislike, m.Name) {
+			return fmt.Sprintf("n'aime pas « %s »", dislike)
+		}
+	}
+
+	return ""
+}
+
+func (s *planSearch) search(day int, score float64, cost int) {
+	if score+s.bounds[day] < s.bestScore {
+		return
+	}
+
+	if day == len(s.candidates) {
+		if score > s.bestScore || (score == s.bestScore && cost < s.bestCost) {
+			s.best = append(s.best[:0], s.current...)
+			s.bestScore = score
+			s.bestCost = cost
+		}
+		return
+	}
+
+	for _, c := range s.candidates[day] {
+		if s.prefs.Budget != nil && cost+c.cost > s.prefs.Budget.Amount {
+			continue
+		}
+		if s.prefs.NoRepeatCategory && s.categories[c.meal.Category] > 0 {
+			continue
+		}
+
+		gain := c.score - varietyPenalty*float64(s.dishes[c.dish])
+		if !s.prefs.NoRepeatCategory {
+			gain -= categoryPenalty * float64(s.categories[c.meal.Category])
+		}
+
+		s.categories[c.meal.Category]++
+		s.dishes[c.dish]++
+		s.current = append(s.current, c)
+		s.search(day+1, score+gain, cost+c.cost)
+		s.current = s.current[:len(s.current)-1]
+		s.dishes[c.dish]--
+		s.categories[c.meal.Category]--
+	}
+
+	s.current = append(s.current, nil)
+	s.search(day+1, score-s.skip, cost)
+	s.current = s.current[:len(s.current)-1]
+}
+
+func isCheapest(c *planCandidate, candidates []*planCandidate) bool {
+	for _, other := range candidates {
+		if other != c && other.meal.Priced && other.cost <= c.cost {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedExclusions(excluded map[string]int) []string {
+	reasons := make([]string, 0, len(excluded))
+	for reason := range excluded {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	return reasons
+}
+
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, c := range p.Choices {
+		b.WriteString(French.FormatDate(c.Date))
+		b.WriteString(" : ")
+		if c.Meal != nil {
+			b.WriteString(c.Meal.Name)
+			if text := c.Meal.PriceText(); text != "" {
+				b.WriteString(" (" + text + ")")
+			}
+		} else {
+			b.WriteString("—")
+		}
+		b.WriteString(", " + strings.Join(c.Reasons, ", ") + "\n")
+	}
+	for _, n := range p.Notes {
+		b.WriteString(n + "\n")
+	}
+
+	return b.String()
+}
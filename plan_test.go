@@ -0,0 +1,138 @@
+package gorldline
+
+import (
+	"testing"
+	"time"
+)
+
+func plannedDays(meals ...[]*Meal) []*Day {
+	days := make([]*Day, 0, len(meals))
+	for i, dishes := range meals {
+		d := &Day{Start: time.Date(2026, 10, 5+i, 0, 0, 0, 0, time.UTC), Meals: make(map[string][]*Meal)}
+		for _, m := range dishes {
+			m.Tags = make(Tags)
+			d.Meals[m.Category] = append(d.Meals[m.Category], m)
+		}
+		days = append(days, d)
+	}
+
+	return days
+}
+
+func plannedMeal(name, category string, cents int) *Meal {
+	m := &Meal{Name: name, Category: category}
+	if cents >= 0 {
+		m.Price, m.Priced = Euros(cents), true
+	}
+
+	return m
+}
+
+func planFilled(p *Plan) int {
+	filled := 0
+	for _, c := range p.Choices {
+		if c.Meal != nil {
+			filled++
+		}
+	}
+
+	return filled
+}
+
+func TestPlanBudget(t *testing.T) {
+	cases := []struct {
+		name   string
+		budget int
+		days   []*Day
+		filled int
+		total  int
+	}{
+		{
+			name:   "unpriced meals are excluded",
+			budget: 2000,
+			days: plannedDays(
+				[]*Meal{plannedMeal("Poulet rôti", "Plat du jour", 500)},
+				[]*Meal{plannedMeal("Couscous", "Plat du jour", -1)},
+				[]*Meal{plannedMeal("Lasagnes", "Plat du jour", 500)},
+				[]*Meal{plannedMeal("Paella", "Plat du jour", -1)},
+				[]*Meal{plannedMeal("Filet de colin", "Plat du jour", 500)},
+			),
+			filled: 3,
+			total:  1500,
+		},
+		{
+			name:   "cheaper dishes keep every day filled",
+			budget: 1200,
+			days: plannedDays(
+				[]*Meal{plannedMeal("Entrecôte", "Grill", 900), plannedMeal("Omelette", "Plat du jour", 400)},
+				[]*Meal{plannedMeal("Entrecôte", "Grill", 900), plannedMeal("Quiche lorraine", "Plat du jour", 400)},
+				[]*Meal{plannedMeal("Entrecôte", "Grill", 900), plannedMeal("Croque-monsieur", "Plat du jour", 400)},
+			),
+			filled: 3,
+			total:  1200,
+		},
+		{
+			name:   "days are left empty once the budget is spent",
+			budget: 1000,
+			days: plannedDays(
+				[]*Meal{plannedMeal("Entrecôte", "Grill", 900)},
+				[]*Meal{plannedMeal("Entrecôte", "Grill", 900)},
+			),
+			filled: 1,
+			total:  900,
+		},
+	}
+
+	for _, c := range cases {
+		budget := Euros(c.budget)
+		p := PlanDays(c.days, &PlanPreferences{Budget: &budget})
+		if filled := planFilled(p); filled != c.filled || p.Total.Amount != c.total {
+			t.Errorf("%s: %d days for %v, want %d days for %v", c.name, filled, p.Total, c.filled, Euros(c.total))
+		}
+		if p.Total.Amount > c.budget {
+			t.Errorf("%s: spent %v over a budget of %v", c.name, p.Total, budget)
+		}
+	}
+}
+
+func TestPlanSkipPenalty(t *testing.T) {
+	cases := map[int]float64{
+		1:  4.5,
+		5:  10.5,
+		12: 21,
+	}
+	for days, want := range cases {
+		if got := skipPenalty(days); got != want {
+			t.Errorf("skipPenalty(%d) = %v, want %v", days, got, want)
+		}
+	}
+
+	for _, n := range []int{1, 5, 12} {
+		meals := make([][]*Meal, n)
+		for i := range meals {
+			m := plannedMeal("Poulet rôti", "Plat du jour", 500)
+			m.Rating = &RatingSummary{Average: 1, Votes: 3, Times: 3}
+			meals[i] = []*Meal{m}
+		}
+
+		if filled := planFilled(PlanDays(plannedDays(meals...), nil)); filled != n {
+			t.Errorf("%d days of the same badly rated dish: %d filled, want %d", n, filled, n)
+		}
+	}
+}
+
+func TestPlanNoRepeatCategory(t *testing.T) {
+	days := plannedDays(
+		[]*Meal{plannedMeal("Entrecôte", "Grill", 900), plannedMeal("Omelette", "Plat du jour", 400)},
+		[]*Meal{plannedMeal("Côte de porc", "Grill", 900)},
+		[]*Meal{plannedMeal("Brochette de poulet", "Grill", 900)},
+	)
+
+	p := PlanDays(days, &PlanPreferences{NoRepeatCategory: true})
+	if filled := planFilled(p); filled != 2 {
+		t.Fatalf("filled %d days, want 2", filled)
+	}
+	if m := p.Choices[0].Meal; m == nil || m.Name != "Omelette" {
+		t.Errorf("first day = %v, want Omelette to leave the grill for later", m)
+	}
+}
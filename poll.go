@@ -0,0 +1,309 @@
+package gorldline
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	pollIdLength    = 8
+	departureFormat = "15:04"
+)
+
+var (
+	ErrPollNotFound         = errors.New("poll not found")
+	ErrPollClosed           = errors.New("poll is closed")
+	ErrEmptyPoll            = errors.New("no stand open this day")
+	ErrInvalidPollOption    = errors.New("unknown stand")
+	ErrInvalidDepartureTime = errors.New("unknown departure time")
+	ErrInvalidPollFile      = errors.New("invalid polls file")
+)
+
+var (
+	DefaultDepartureTimes = []string{"11:30", "11:45", "12:00", "12:15", "12:30", "12:45", "13:00"}
+)
+
+type PollOption struct {
+	ID    string   `json:"id"`
+	Label string   `json:"label"`
+	Meals []string `json:"meals"`
+}
+
+type PollVote struct {
+	Name   string    `json:"name"`
+	Option string    `json:"option"`
+	Time   string    `json:"time"`
+	Cast   time.Time `json:"cast"`
+}
+
+type Poll struct {
+	ID      string               `json:"id"`
+	Title   string               `json:"title"`
+	Date    time.Time            `json:"date"`
+	Options []*PollOption        `json:"options"`
+	Times   []string             `json:"times"`
+	Closes  time.Time            `json:"closes"`
+	Votes   map[string]*PollVote `json:"votes"`
+}
+
+type PollOptionResult struct {
+	*PollOption
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+type PollTimeResult struct {
+	Time   string   `json:"time"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+type PollResults struct {
+	ID      string              `json:"id"`
+	Title   string              `json:"title"`
+	Date    time.Time           `json:"date"`
+	Closes  time.Time           `json:"closes"`
+	Closed  bool                `json:"closed"`
+	Total   int                 `json:"total"`
+	Options []*PollOptionResult `json:"options"`
+	Times   []*PollTimeResult   `json:"times"`
+}
+
+func NewPoll(d *Day, title string, times []string, closes time.Time) (*Poll, error) {
+	p := new(Poll)
+	p.Title = title
+	p.Date = d.Start
+	p.Closes = closes
+	p.Votes = make(map[string]*PollVote)
+
+	for _, c := range d.OrderedCategories() {
+		if c.IsSide() || len(d.Meals[c.Label]) == 0 {
+			continue
+		}
+
+		o := &PollOption{ID: c.ID, Label: c.Label}
+		for _, m := range d.Meals[c.Label] {
+			o.Meals = append(o.Meals, m.Name)
+		}
+		p.Options = append(p.Options, o)
+	}
+
+	if len(p.Options) == 0 {
+		return nil, ErrEmptyPoll
+	}
+
+	if len(times) == 0 {
+		times = DefaultDepartureTimes
+	}
+	for _, t := range times {
+		if _, err := time.Parse(departureFormat, t); err != nil {
+			return nil, ErrInvalidDepartureTime
+		}
+	}
+	p.Times = times
+
+	id := make([]byte, pollIdLength)
+	_, err := rand.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = hex.EncodeToString(id)
+
+	return p, nil
+}
+
+func AtClock(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse(departureFormat, clock)
+	if err != nil {
+		return timeZero, err
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
+
+func (p *Poll) Closed(now time.Time) bool {
+	return !now.Before(p.Closes)
+}
+
+// Each user has a single vote, voting again replaces the previous choice.
+func (p *Poll) Vote(user, name, option, departure string, now time.Time) error {
+	if p.Closed(now) {
+		return ErrPollClosed
+	}
+	if strings.TrimSpace(user) == "" {
+		return ErrMissingUserToken
+	}
+
+	found := false
+	for _, o := range p.Options {
+		if o.ID == option {
+			found = true
+		}
+	}
+	if !found {
+		return ErrInvalidPollOption
+	}
+	if !containsString(p.Times, departure) {
+		return ErrInvalidDepartureTime
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Anonyme"
+	}
+
+	p.Votes[hashUserToken(user)] = &PollVote{name, option, departure, now}
+	return nil
+}
+
+func (p *Poll) VoteOf(user string) *PollVote {
+	return p.Votes[hashUserToken(user)]
+}
+
+func (p *Poll) Results(now time.Time) *PollResults {
+	r := new(PollResults)
+	r.ID = p.ID
+	r.Title = p.Title
+	r.Date = p.Date
+	r.Closes = p.Closes
+	r.Closed = p.Closed(now)
+	r.Total = len(p.Votes)
+
+	votes := make([]*PollVote, 0, len(p.Votes))
+	for _, v := range p.Votes {
+		votes = append(votes, v)
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		return votes[i].Cast.Before(votes[j].Cast)
+	})
+
+	for _, o := range p.Options {
+		result := &PollOptionResult{o, 0, []string{}}
+		for _, v := range votes {
+			if v.Option == o.ID {
+				result.Votes++
+				result.Voters = append(result.Voters, v.Name)
+			}
+		}
+		r.Options = append(r.Options, result)
+	}
+
+	for _, t := range p.Times {
+		result := &PollTimeResult{t, 0, []string{}}
+		for _, v := range votes {
+			if v.Time == t {
+				result.Votes++
+				result.Voters = append(result.Voters, v.Name)
+			}
+		}
+		r.Times = append(r.Times, result)
+	}
+
+	return r
+}
+
+// The store is read again before each operation, so that cmd/api and cmd/web can share the same file.
+type PollStore struct {
+	path string
+	lock sync.Mutex
+}
+
+func NewPollStore(path string) *PollStore {
+	s := new(PollStore)
+	s.path = path
+
+	return s
+}
+
+func (s *PollStore) Create(p *Poll) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	polls, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	polls[p.ID] = p
+	return s.save(polls)
+}
+
+func (s *PollStore) Get(id string) (*Poll, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	polls, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	p, pres := polls[id]
+	if !pres {
+		return nil, ErrPollNotFound
+	}
+
+	return p, nil
+}
+
+func (s *PollStore) Vote(id, user, name, option, departure string, now time.Time) (*Poll, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	polls, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	p, pres := polls[id]
+	if !pres {
+		return nil, ErrPollNotFound
+	}
+
+	err = p.Vote(user, name, option, departure, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, s.save(polls)
+}
+
+func (s *PollStore) load() (map[string]*Poll, error) {
+	polls := make(map[string]*Poll)
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return polls, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	err = json.NewDecoder(file).Decode(&polls)
+	if err != nil {
+		return nil, ErrInvalidPollFile
+	}
+
+	return polls, nil
+}
+
+// Polls of previous days are dropped when saving.
+func (s *PollStore) save(polls map[string]*Poll) error {
+	for id, p := range polls {
+		if p.Date.Before(midnight(time.Now().In(p.Date.Location()))) {
+			delete(polls, id)
+		}
+	}
+
+	return writeJsonFile(s.path, polls)
+}
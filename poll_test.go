@@ -0,0 +1,122 @@
+package gorldline
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func pollDay(start time.Time) *Day {
+	return &Day{Start: start, Meals: map[string][]*Meal{
+		"Plat du jour": {{Name: "Poulet rôti", Tags: make(Tags)}},
+		"Grill":        {{Name: "Entrecôte", Tags: make(Tags)}, {Name: "Merguez", Tags: make(Tags)}},
+		"Dessert":      {{Name: "Tarte aux pommes", Tags: make(Tags)}},
+	}}
+}
+
+func TestNewPoll(t *testing.T) {
+	start := midnight(time.Now())
+	closes := start.Add(11 * time.Hour)
+
+	p, err := NewPoll(pollDay(start), "Midi", nil, closes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Options) != 2 || p.Options[0].ID != CategoryDailyDish.ID || len(p.Options[1].Meals) != 2 {
+		t.Errorf("options = %+v, want the daily dish and the grill with two meals", p.Options)
+	}
+	if len(p.Times) != len(DefaultDepartureTimes) {
+		t.Errorf("times = %v, want %v", p.Times, DefaultDepartureTimes)
+	}
+
+	_, err = NewPoll(pollDay(start), "Midi", []string{"12h"}, closes)
+	if err != ErrInvalidDepartureTime {
+		t.Errorf("NewPoll with time %q error = %v, want %v", "12h", err, ErrInvalidDepartureTime)
+	}
+
+	empty := &Day{Start: start, Meals: map[string][]*Meal{"Dessert": {{Name: "Flan", Tags: make(Tags)}}}}
+	_, err = NewPoll(empty, "Midi", nil, closes)
+	if err != ErrEmptyPoll {
+		t.Errorf("NewPoll with desserts only error = %v, want %v", err, ErrEmptyPoll)
+	}
+}
+
+func TestPollVote(t *testing.T) {
+	start := midnight(time.Now())
+	closes := start.Add(11 * time.Hour)
+	before := closes.Add(-time.Hour)
+
+	p, err := NewPoll(pollDay(start), "Midi", []string{"12:00", "12:30"}, closes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		user      string
+		option    string
+		departure string
+		now       time.Time
+		want      error
+	}{
+		{"alice", CategoryDailyDish.ID, "12:00", before, nil},
+		{"bob", CategoryDailyDish.ID, "12:30", before, nil},
+		{"bob", CategoryGrill.ID, "12:00", before, nil},
+		{"carol", "dessert", "12:00", before, ErrInvalidPollOption},
+		{"carol", CategoryGrill.ID, "13:00", before, ErrInvalidDepartureTime},
+		{" ", CategoryGrill.ID, "12:00", before, ErrMissingUserToken},
+		{"carol", CategoryGrill.ID, "12:00", closes, ErrPollClosed},
+	}
+	for _, c := range cases {
+		if err := p.Vote(c.user, c.user, c.option, c.departure, c.now); err != c.want {
+			t.Errorf("Vote(%q, %q, %q) = %v, want %v", c.user, c.option, c.departure, err, c.want)
+		}
+	}
+
+	r := p.Results(before)
+	if r.Closed || r.Total != 2 {
+		t.Fatalf("results = %+v, want 2 votes on an open poll", r)
+	}
+	for i, want := range []int{1, 1} {
+		if r.Options[i].Votes != want {
+			t.Errorf("option %s has %d votes, want %d", r.Options[i].ID, r.Options[i].Votes, want)
+		}
+	}
+	if r.Times[0].Votes != 2 || r.Times[1].Votes != 0 {
+		t.Errorf("times = %+v, want both votes at 12:00", r.Times)
+	}
+	if !p.Results(closes).Closed {
+		t.Error("results at closing time are not closed")
+	}
+}
+
+func TestPollStoreSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "polls.json")
+	start := midnight(time.Now())
+	closes := start.Add(24 * time.Hour)
+
+	writer, reader := NewPollStore(path), NewPollStore(path)
+	p, err := NewPoll(pollDay(start), "Midi", nil, closes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = writer.Create(p); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = reader.Vote(p.ID, "alice", "Alice", CategoryGrill.ID, "12:00", start)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := writer.Get(p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := got.VoteOf("alice"); v == nil || v.Name != "Alice" || v.Option != CategoryGrill.ID {
+		t.Errorf("VoteOf(alice) = %+v, want Alice at the grill", v)
+	}
+
+	if _, err = reader.Get("missing"); err != ErrPollNotFound {
+		t.Errorf("Get(missing) error = %v, want %v", err, ErrPollNotFound)
+	}
+}
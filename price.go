@@ -0,0 +1,158 @@
+package gorldline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultCurrency = "EUR"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("cannot combine prices with different currencies")
+)
+
+var (
+	currencySymbols = map[string]string{
+		"EUR": "€",
+		"USD": "$",
+		"GBP": "£",
+		"CHF": "CHF",
+	}
+
+	freeWords = []string{"gratuit", "gratuite", "offert", "offerte", "free"}
+
+	bareDigitsRegex = regexp.MustCompile(`^\d+$`)
+	priceRegex      = regexp.MustCompile(`(?i)^(?:€\s*)?(\d+)(?:\s*(?:[.,]|€)\s*(\d{0,2}))?\s*(?:€|eur|euros?)?$`)
+)
+
+type Price struct {
+	Amount   int    `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func NewPrice(amount int, currency string) Price {
+	return Price{amount, currency}
+}
+
+func Euros(cents int) Price {
+	return Price{cents, DefaultCurrency}
+}
+
+func (p Price) IsZero() bool {
+	return p.Amount == 0
+}
+
+func (p Price) Add(other Price) (Price, error) {
+	currency, err := p.combine(other)
+	if err != nil {
+		return Price{}, err
+	}
+
+	return Price{p.Amount + other.Amount, currency}, nil
+}
+
+func (p Price) Sub(other Price) (Price, error) {
+	currency, err := p.combine(other)
+	if err != nil {
+		return Price{}, err
+	}
+
+	return Price{p.Amount - other.Amount, currency}, nil
+}
+
+func (p Price) Mul(n int) Price {
+	return Price{p.Amount * n, p.Currency}
+}
+
+func (p Price) combine(other Price) (string, error) {
+	switch {
+	case p.Currency == other.Currency:
+		return p.Currency, nil
+	case p.Currency == "":
+		return other.Currency, nil
+	case other.Currency == "":
+		return p.Currency, nil
+	default:
+		return "", ErrCurrencyMismatch
+	}
+}
+
+func (p Price) Format(l *Language) string {
+	if l == nil {
+		l = French
+	}
+
+	amount, sign := p.Amount, ""
+	if amount < 0 {
+		amount, sign = -amount, "-"
+	}
+
+	symbol, pres := currencySymbols[p.Currency]
+	if !pres {
+		symbol = p.Currency
+	}
+
+	number := fmt.Sprintf("%d%s%02d", amount/100, l.decimal, amount%100)
+	return sign + fmt.Sprintf(l.priceFormat, number, symbol)
+}
+
+func (p Price) String() string {
+	return p.Format(French)
+}
+
+func SumPrices(prices ...Price) (Price, error) {
+	var total Price
+	for _, p := range prices {
+		var err error
+		total, err = total.Add(p)
+		if err != nil {
+			return Price{}, err
+		}
+	}
+
+	return total, nil
+}
+
+// Bare integers are read as cents, like the menu sheets write them: "350" is 3,50 €.
+// A separator or a currency makes the integer part euros: "3,50 €", "3.50", "3€50" or "20€".
+func ParsePrice(s string) (Price, bool) {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return Price{}, false
+	}
+
+	lower := strings.ToLower(s)
+	for _, w := range freeWords {
+		if lower == w {
+			return Euros(0), true
+		}
+	}
+
+	matches := priceRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return Price{}, false
+	}
+
+	units, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return Price{}, false
+	}
+	if bareDigitsRegex.MatchString(s) {
+		return Euros(units), true
+	}
+
+	cents := 0
+	if matches[2] != "" {
+		cents, err = strconv.Atoi((matches[2] + "0")[:2])
+		if err != nil {
+			return Price{}, false
+		}
+	}
+
+	return Euros(units*100 + cents), true
+}
@@ -0,0 +1,72 @@
+package gorldline
+
+import (
+	"testing"
+)
+
+func TestParsePrice(t *testing.T) {
+	cases := []struct {
+		in     string
+		cents  int
+		priced bool
+	}{
+		{"350", 350, true},
+		{" 1200 ", 1200, true},
+		{"3,50 €", 350, true},
+		{"3.50", 350, true},
+		{"3€50", 350, true},
+		{"3,5", 350, true},
+		{"20€", 2000, true},
+		{"€ 4,20", 420, true},
+		{"5 euros", 500, true},
+		{"Gratuit", 0, true},
+		{"offert", 0, true},
+		{"CJ", 0, false},
+		{"", 0, false},
+		{"  ", 0, false},
+		{"3,50 $", 0, false},
+		{"1.234,50", 0, false},
+	}
+
+	for _, c := range cases {
+		p, priced := ParsePrice(c.in)
+		if priced != c.priced || p.Amount != c.cents {
+			t.Errorf("ParsePrice(%q) = %d, %v, want %d, %v", c.in, p.Amount, priced, c.cents, c.priced)
+		}
+		if priced && p.Currency != DefaultCurrency {
+			t.Errorf("ParsePrice(%q) currency = %q, want %q", c.in, p.Currency, DefaultCurrency)
+		}
+	}
+}
+
+func TestPriceFormat(t *testing.T) {
+	cases := []struct {
+		price    Price
+		language *Language
+		want     string
+	}{
+		{Euros(350), French, "3,50 €"},
+		{Euros(350), English, "€3.50"},
+		{Euros(5), French, "0,05 €"},
+		{Euros(-120), French, "-1,20 €"},
+		{NewPrice(1000, "CHF"), German, "10,00 CHF"},
+	}
+
+	for _, c := range cases {
+		if got := c.price.Format(c.language); got != c.want {
+			t.Errorf("Format(%v, %s) = %q, want %q", c.price, c.language.Code, got, c.want)
+		}
+	}
+}
+
+func TestSumPrices(t *testing.T) {
+	total, err := SumPrices(Euros(350), Euros(120), Euros(5))
+	if err != nil || total != Euros(475) {
+		t.Errorf("SumPrices = %v, %v, want %v", total, err, Euros(475))
+	}
+
+	_, err = SumPrices(Euros(350), NewPrice(100, "USD"))
+	if err != ErrCurrencyMismatch {
+		t.Errorf("SumPrices error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
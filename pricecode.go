@@ -0,0 +1,121 @@
+package gorldline
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrInvalidPriceCodesFile = errors.New("invalid price codes file")
+)
+
+var (
+	priceCodes = map[string]PriceCode{
+		"CJ": {Code: "CJ", Label: "Formule du jour"},
+	}
+	priceCodesLock sync.RWMutex
+
+	priceCodeRegex = regexp.MustCompile(`^[\p{L}]{1,4}$`)
+)
+
+type PriceCode struct {
+	Code   string `json:"code"`
+	Label  string `json:"label"`
+	Amount *Price `json:"amount,omitempty"`
+}
+
+func (c *PriceCode) String() string {
+	if c.Label != "" {
+		return c.Label
+	}
+
+	return c.Code
+}
+
+func SetPriceCode(code string, label string, amount *Price) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	priceCodesLock.Lock()
+	defer priceCodesLock.Unlock()
+
+	priceCodes[code] = PriceCode{code, label, amount}
+}
+
+func LookupPriceCode(code string) (*PriceCode, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	priceCodesLock.RLock()
+	defer priceCodesLock.RUnlock()
+
+	c, pres := priceCodes[code]
+	if !pres {
+		return nil, false
+	}
+
+	return &c, true
+}
+
+func PriceCodes() []*PriceCode {
+	priceCodesLock.RLock()
+	defer priceCodesLock.RUnlock()
+
+	codes := make([]*PriceCode, 0, len(priceCodes))
+	for _, c := range priceCodes {
+		c := c
+		codes = append(codes, &c)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i].Code < codes[j].Code
+	})
+
+	return codes
+}
+
+func LoadPriceCodesFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var entries map[string]struct {
+		Label  string `json:"label"`
+		Amount *int   `json:"amount"`
+	}
+	err = json.NewDecoder(file).Decode(&entries)
+	if err != nil {
+		return ErrInvalidPriceCodesFile
+	}
+
+	for code, e := range entries {
+		var amount *Price
+		if e.Amount != nil {
+			p := Euros(*e.Amount)
+			amount = &p
+		}
+		SetPriceCode(code, e.Label, amount)
+	}
+
+	return nil
+}
+
+func parsePriceCode(s string) *PriceCode {
+	s = strings.TrimSpace(s)
+	if !priceCodeRegex.MatchString(s) {
+		return nil
+	}
+
+	if c, pres := LookupPriceCode(s); pres {
+		return c
+	}
+
+	return &PriceCode{Code: strings.ToUpper(s)}
+}
@@ -0,0 +1,292 @@
+package gorldline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	MinStars = 1
+	MaxStars = 5
+
+	maxCommentLength = 500
+	// Only the most recent servings of a dish count towards its average.
+	ratedServings = 3
+)
+
+var (
+	ErrInvalidRating     = errors.New("rating must be between 1 and 5 stars")
+	ErrMissingUserToken  = errors.New("missing user token")
+	ErrRatingClosed      = errors.New("meals can only be rated on the day they are served")
+	ErrAlreadyRated      = errors.New("meal already rated by this user")
+	ErrInvalidRatingFile = errors.New("invalid ratings file")
+)
+
+var (
+	ratingServingsFormats = map[string][]string{
+		"fr": {"la dernière fois", "les %d dernières fois"},
+		"en": {"last time", "last %d times"},
+		"de": {"letztes Mal", "letzte %d Male"},
+		"es": {"la última vez", "las últimas %d veces"},
+	}
+)
+
+type Review struct {
+	MealID  string    `json:"meal"`
+	Dish    string    `json:"dish"`
+	Date    time.Time `json:"date"`
+	User    string    `json:"user,omitempty"`
+	Stars   int       `json:"stars"`
+	Comment string    `json:"comment,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Votes   int     `json:"votes"`
+	Times   int     `json:"times"`
+}
+
+func (s *RatingSummary) Format(l *Language) string {
+	if l == nil {
+		l = French
+	}
+
+	average := strings.Replace(strconv.FormatFloat(s.Average, 'f', 1, 64), ".", l.decimal, 1)
+	servings := strings.Replace(l.Plural(s.Times, ratingServingsFormats[l.Code]...), "%d", strconv.Itoa(s.Times), 1)
+	return fmt.Sprintf("★%s %s", average, servings)
+}
+
+func (s *RatingSummary) String() string {
+	return s.Format(French)
+}
+
+// Dishes served on different days share the same key as long as their normalised names match.
+func DishKey(name string) string {
+	return strings.Join(searchTerms(DefaultNormalizer.Normalize(name)), " ")
+}
+
+// The file is shared with other processes, it is read again whenever it has been replaced since the last read.
+type RatingStore struct {
+	path    string
+	lock    sync.Mutex
+	reviews []*Review
+	info    os.FileInfo
+}
+
+func NewRatingStore(path string) (*RatingStore, error) {
+	s := new(RatingStore)
+	s.path = path
+	s.reviews = make([]*Review, 0)
+
+	err := s.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *RatingStore) refresh() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.reviews = s.reviews[:0]
+			s.info = nil
+			return nil
+		}
+		return err
+	}
+	if s.info != nil && os.SameFile(s.info, info) && s.info.ModTime().Equal(info.ModTime()) && s.info.Size() == info.Size() {
+		return nil
+	}
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reviews := make([]*Review, 0)
+	err = json.NewDecoder(file).Decode(&reviews)
+	if err != nil {
+		return ErrInvalidRatingFile
+	}
+
+	s.reviews = reviews
+	s.info = info
+	return nil
+}
+
+func (s *RatingStore) Rate(m *Meal, user string, stars int, comment string, now time.Time) (*Review, error) {
+	if stars < MinStars || stars > MaxStars {
+		return nil, ErrInvalidRating
+	}
+	if strings.TrimSpace(user) == "" {
+		return nil, ErrMissingUserToken
+	}
+	if !midnight(now.In(m.Date.Location())).Equal(m.Date) {
+		return nil, ErrRatingClosed
+	}
+
+	comment = strings.TrimSpace(comment)
+	if runes := []rune(comment); len(runes) > maxCommentLength {
+		comment = string(runes[:maxCommentLength])
+	}
+
+	r := new(Review)
+	r.MealID = m.ID
+	r.Dish = DishKey(m.Name)
+	r.Date = m.Date
+	r.User = hashUserToken(user)
+	r.Stars = stars
+	r.Comment = comment
+	r.Created = now
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	err := s.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, other := range s.reviews {
+		if other.MealID == r.MealID && other.User == r.User {
+			return nil, ErrAlreadyRated
+		}
+	}
+
+	s.reviews = append(s.reviews, r)
+	err = s.save()
+	if err != nil {
+		s.reviews = s.reviews[:len(s.reviews)-1]
+		return nil, err
+	}
+
+	return r.public(), nil
+}
+
+func (s *RatingStore) Summary(name string) *RatingSummary {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// A file being rewritten by another process is read on the next call, the last reviews are kept meanwhile.
+	_ = s.refresh()
+
+	return s.summary(DishKey(name))
+}
+
+// The average only covers the last rated servings of the dish, so that a recipe change is quickly reflected.
+func (s *RatingStore) summary(dish string) *RatingSummary {
+	days := make([]time.Time, 0)
+	for _, r := range s.reviews {
+		if r.Dish == dish && !containsTime(days, r.Date) {
+			days = append(days, r.Date)
+		}
+	}
+	if len(days) == 0 {
+		return nil
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].After(days[j])
+	})
+	if len(days) > ratedServings {
+		days = days[:ratedServings]
+	}
+
+	total, votes := 0, 0
+	for _, r := range s.reviews {
+		if r.Dish == dish && containsTime(days, r.Date) {
+			total += r.Stars
+			votes++
+		}
+	}
+
+	return &RatingSummary{math.Round(float64(total)/float64(votes)*10) / 10, votes, len(days)}
+}
+
+// Reviews returns the reviews of a dish, most recent first, without the user tokens.
+func (s *RatingStore) Reviews(name string) []*Review {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_ = s.refresh()
+
+	dish := DishKey(name)
+	reviews := make([]*Review, 0)
+	for _, r := range s.reviews {
+		if r.Dish == dish {
+			reviews = append(reviews, r.public())
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		return reviews[i].Created.After(reviews[j].Created)
+	})
+
+	return reviews
+}
+
+func (s *RatingStore) Annotate(days []*Day) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_ = s.refresh()
+
+	for _, d := range days {
+		for _, meals := range d.Meals {
+			for _, m := range meals {
+				m.Rating = s.summary(DishKey(m.Name))
+			}
+		}
+	}
+}
+
+func (s *RatingStore) save() error {
+	err := writeJsonFile(s.path, s.reviews)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(s.path)
+	if err == nil {
+		s.info = info
+	}
+
+	return nil
+}
+
+func (r *Review) public() *Review {
+	copied := *r
+	copied.User = ""
+	return &copied
+}
+
+func containsTime(list []time.Time, t time.Time) bool {
+	for _, e := range list {
+		if e.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,108 @@
+package gorldline
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func servedMeal(id, name string, date time.Time) *Meal {
+	return &Meal{ID: id, Name: name, Date: date}
+}
+
+func TestRatingStoreSharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratings.json")
+	date := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	m := servedMeal("a", "Poulet rôti", date)
+
+	writer, err := NewRatingStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := NewRatingStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := reader.Summary(m.Name); s != nil {
+		t.Fatalf("Summary before rating = %v, want nil", s)
+	}
+
+	_, err = writer.Rate(m, "alice", 4, "", date.Add(12*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := reader.Summary(m.Name)
+	if s == nil || s.Votes != 1 || s.Average != 4 {
+		t.Fatalf("Summary after rating = %+v, want 1 vote of 4", s)
+	}
+
+	_, err = reader.Rate(m, "alice", 5, "", date.Add(13*time.Hour))
+	if err != ErrAlreadyRated {
+		t.Fatalf("second Rate error = %v, want %v", err, ErrAlreadyRated)
+	}
+
+	_, err = reader.Rate(m, "bob", 2, "", date.Add(13*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := writer.Summary(m.Name); s == nil || s.Votes != 2 || s.Average != 3 {
+		t.Fatalf("writer Summary = %+v, want 2 votes averaging 3", s)
+	}
+}
+
+func TestRatingSummaryLastServings(t *testing.T) {
+	store, err := NewRatingStore(filepath.Join(t.TempDir(), "ratings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ratings := []struct {
+		day   int
+		user  string
+		stars int
+	}{
+		{1, "alice", 1},
+		{8, "alice", 5},
+		{15, "alice", 4},
+		{15, "bob", 3},
+		{22, "alice", 5},
+	}
+	for i, r := range ratings {
+		date := time.Date(2026, 10, r.day, 0, 0, 0, 0, time.UTC)
+		m := servedMeal(date.Format("20060102"), "Poulets rôtis", date)
+		_, err = store.Rate(m, r.user, r.stars, "", date.Add(12*time.Hour))
+		if err != nil {
+			t.Fatalf("Rate #%d: %v", i, err)
+		}
+	}
+
+	s := store.Summary("Poulet rôti")
+	if s == nil {
+		t.Fatal("Summary = nil")
+	}
+	if s.Average != 4.3 || s.Votes != 4 || s.Times != 3 {
+		t.Errorf("Summary = %+v, want average 4.3 over 4 votes and 3 servings", s)
+	}
+
+	cases := []struct {
+		language *Language
+		want     string
+	}{
+		{French, "★4,3 les 3 dernières fois"},
+		{English, "★4.3 last 3 times"},
+		{German, "★4,3 letzte 3 Male"},
+		{Spanish, "★4,3 las últimas 3 veces"},
+	}
+	for _, c := range cases {
+		if got := s.Format(c.language); got != c.want {
+			t.Errorf("Format(%s) = %q, want %q", c.language.Code, got, c.want)
+		}
+	}
+
+	one := &RatingSummary{Average: 5, Votes: 1, Times: 1}
+	if got := one.Format(English); got != "★5.0 last time" {
+		t.Errorf("Format(en) = %q, want %q", got, "★5.0 last time")
+	}
+}
@@ -0,0 +1,161 @@
+package gorldline
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+type SearchQuery struct {
+	Text     string
+	From     time.Time
+	To       time.Time
+	Category string
+	MinPrice *Price
+	MaxPrice *Price
+	Limit    int
+}
+
+type SearchIndex struct {
+	meals    map[string]*Meal
+	postings map[string]map[string]struct{}
+}
+
+func NewSearchIndex() *SearchIndex {
+	i := new(SearchIndex)
+	i.meals = make(map[string]*Meal)
+	i.postings = make(map[string]map[string]struct{})
+
+	return i
+}
+
+func (i *SearchIndex) Len() int {
+	return len(i.meals)
+}
+
+func (i *SearchIndex) AddDays(days []*Day) {
+	for _, d := range days {
+		for label, meals := range d.Meals {
+			category := CategoryByLabel(label)
+			for _, m := range meals {
+				i.add(m, category)
+			}
+		}
+	}
+}
+
+func (i *SearchIndex) AddList(l *List) error {
+	days, err := l.Days()
+	if err != nil {
+		return err
+	}
+
+	i.AddDays(days)
+	return nil
+}
+
+func (i *SearchIndex) add(m *Meal, category *Category) {
+	i.meals[m.ID] = m
+
+	for _, term := range searchTerms(m.Name + " " + category.Name(French)) {
+		ids, pres := i.postings[term]
+		if !pres {
+			ids = make(map[string]struct{})
+			i.postings[term] = ids
+		}
+		ids[m.ID] = struct{}{}
+	}
+}
+
+// Every query term must match, the last one may be a prefix so that results show up while typing.
+func (i *SearchIndex) Search(q *SearchQuery) []*Meal {
+	terms := searchTerms(q.Text)
+
+	var candidates map[string]struct{}
+	if len(terms) == 0 {
+		candidates = make(map[string]struct{}, len(i.meals))
+		for id := range i.meals {
+			candidates[id] = struct{}{}
+		}
+	}
+
+	for n, term := range terms {
+		matched := make(map[string]struct{})
+		for id := range i.postings[term] {
+			matched[id] = struct{}{}
+		}
+		if n == len(terms)-1 {
+			for indexed, ids := range i.postings {
+				if strings.HasPrefix(indexed, term) {
+					for id := range ids {
+						matched[id] = struct{}{}
+					}
+				}
+			}
+		}
+
+		if candidates == nil {
+			candidates = matched
+			continue
+		}
+		for id := range candidates {
+			if _, pres := matched[id]; !pres {
+				delete(candidates, id)
+			}
+		}
+	}
+
+	results := make([]*Meal, 0, len(candidates))
+	for id := range candidates {
+		if m := i.meals[id]; q.matches(m) {
+			results = append(results, m)
+		}
+	}
+
+	sort.Slice(results, func(a, b int) bool {
+		if !results[a].Date.Equal(results[b].Date) {
+			return results[a].Date.After(results[b].Date)
+		}
+		return results[a].Name < results[b].Name
+	})
+
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+
+	return results
+}
+
+func (q *SearchQuery) matches(m *Meal) bool {
+	if !q.From.IsZero() && m.Date.Before(midnight(q.From)) {
+		return false
+	}
+	if !q.To.IsZero() && m.Date.After(q.To) {
+		return false
+	}
+	if q.Category != "" && m.Category != q.Category && m.Category != CategoryByLabel(q.Category).ID {
+		return false
+	}
+	if (q.MinPrice != nil || q.MaxPrice != nil) && !m.Priced {
+		return false
+	}
+	if q.MinPrice != nil && m.Price.Amount < q.MinPrice.Amount {
+		return false
+	}
+	if q.MaxPrice != nil && m.Price.Amount > q.MaxPrice.Amount {
+		return false
+	}
+
+	return true
+}
+
+func searchTerms(s string) []string {
+	terms := make([]string, 0)
+	for _, stem := range meaningfulWords(s) {
+		if !containsString(terms, stem) {
+			terms = append(terms, stem)
+		}
+	}
+
+	return terms
+}
@@ -0,0 +1,114 @@
+package gorldline
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+)
+
+var (
+	ErrEmptyTray          = errors.New("no meal selected")
+	ErrUnpricedMeal       = errors.New("selected meal has no known price")
+	ErrInvalidSubsidyFile = errors.New("invalid subsidy rules file")
+)
+
+var (
+	DefaultSubsidyRules = new(SubsidyRules)
+)
+
+type SubsidyRules struct {
+	AdmissionFee        Price `json:"admissionFee"`
+	Contribution        Price `json:"contribution"`
+	MaxContributionRate int   `json:"maxContributionRate"`
+	VoucherValue        Price `json:"voucherValue"`
+}
+
+type Quote struct {
+	Meals        []*Meal `json:"meals"`
+	Subtotal     Price   `json:"subtotal"`
+	AdmissionFee Price   `json:"admissionFee"`
+	Gross        Price   `json:"gross"`
+	Contribution Price   `json:"contribution"`
+	Voucher      Price   `json:"voucher"`
+	Net          Price   `json:"net"`
+}
+
+func LoadSubsidyRulesFile(path string) (*SubsidyRules, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	rules := new(SubsidyRules)
+	err = json.NewDecoder(file).Decode(rules)
+	if err != nil {
+		return nil, ErrInvalidSubsidyFile
+	}
+
+	return rules, nil
+}
+
+func (r *SubsidyRules) Quote(meals ...*Meal) (*Quote, error) {
+	if len(meals) == 0 {
+		return nil, ErrEmptyTray
+	}
+
+	q := new(Quote)
+	q.Meals = meals
+
+	for _, m := range meals {
+		if !m.Priced {
+			return nil, ErrUnpricedMeal
+		}
+
+		var err error
+		q.Subtotal, err = q.Subtotal.Add(m.Price)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	q.AdmissionFee = r.AdmissionFee
+	q.Gross, err = q.Subtotal.Add(r.AdmissionFee)
+	if err != nil {
+		return nil, err
+	}
+
+	// The employer never pays more than the tray itself, nor more than the configured share of it.
+	q.Contribution = minPrice(r.Contribution, q.Gross)
+	if r.MaxContributionRate > 0 {
+		q.Contribution = minPrice(q.Contribution, Price{q.Gross.Amount * r.MaxContributionRate / 100, q.Gross.Currency})
+	}
+
+	remaining, err := q.Gross.Sub(q.Contribution)
+	if err != nil {
+		return nil, err
+	}
+
+	q.Voucher = minPrice(r.VoucherValue, remaining)
+	q.Net, err = remaining.Sub(q.Voucher)
+	if err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+func minPrice(a, b Price) Price {
+	if a.Amount < 0 {
+		a.Amount = 0
+	}
+	if b.Amount < a.Amount {
+		return b
+	}
+	if a.Currency == "" {
+		a.Currency = b.Currency
+	}
+
+	return a
+}
@@ -0,0 +1,337 @@
+package gorldline
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidGlossary = errors.New("invalid glossary file")
+)
+
+var (
+	wordRegex = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+	elisions = map[string]string{
+		"d":  "of ",
+		"l":  "",
+		"qu": "that ",
+	}
+
+	glossaries = map[string]map[string]string{
+		"en": {
+			// Dishes.
+			"poulet roti":           "roast chicken",
+			"roti de porc":          "roast pork",
+			"roti de boeuf":         "roast beef",
+			"gratin dauphinois":     "potato gratin",
+			"blanquette de veau":    "veal blanquette (creamy veal stew)",
+			"boeuf bourguignon":     "beef bourguignon (red wine beef stew)",
+			"hachis parmentier":     "shepherd's pie",
+			"croque monsieur":       "toasted ham and cheese sandwich",
+			"quiche lorraine":       "bacon quiche",
+			"moules frites":         "mussels and fries",
+			"steak hache":           "beef patty",
+			"cordon bleu":           "cordon bleu",
+			"pot au feu":            "beef stew",
+			"tartiflette":           "potato, bacon and reblochon bake",
+			"choucroute":            "sauerkraut with sausages",
+			"cassoulet":             "bean and sausage stew",
+			"carbonade flamande":    "Flemish beef and beer stew",
+			"potjevleesch":          "Flemish cold meat terrine",
+			"welsh":                 "welsh rarebit",
+			"lasagnes":              "lasagne",
+			"tajine":                "tagine",
+			"omelette":              "omelette",
+			"tarte aux pommes":      "apple tart",
+			"mousse au chocolat":    "chocolate mousse",
+			"ile flottante":         "floating island",
+			"creme brulee":          "crème brûlée",
+			"riz au lait":           "rice pudding",
+			"salade de fruits":      "fruit salad",
+			"fromage blanc":         "quark",
+			"plat du jour":          "dish of the day",
+			"cuisine du monde":      "world cuisine",
+			"bar a legumes":         "vegetable bar",
+			"pommes de terre":       "potatoes",
+			"pommes vapeur":         "steamed potatoes",
+			"pommes sautees":        "sautéed potatoes",
+			"pommes rissolees":      "roast potatoes",
+			"puree":                 "mashed potatoes",
+			"frites":                "fries",
+			"potatoes":              "potato wedges",
+			"riz pilaf":             "pilaf rice",
+			"riz":                   "rice",
+			"pates":                 "pasta",
+			"semoule":               "semolina",
+			"haricots verts":        "green beans",
+			"haricots blancs":       "white beans",
+			"petits pois":           "peas",
+			"chou fleur":            "cauliflower",
+			"chou-fleur":            "cauliflower",
+			"choux de bruxelles":    "Brussels sprouts",
+			"legumes":               "vegetables",
+			"legumes de saison":     "seasonal vegetables",
+			"jardiniere de legumes": "mixed vegetables",
+			"ratatouille":           "ratatouille",
+			"salade verte":          "green salad",
+			"salade":                "salad",
+			"carottes":              "carrots",
+			"courgettes":            "courgettes",
+			"epinards":              "spinach",
+			"champignons":           "mushrooms",
+			"brocolis":              "broccoli",
+			"poireaux":              "leeks",
+			"tomates":               "tomatoes",
+			"tomate":                "tomato",
+			"oignons":               "onions",
+			"lentilles":             "lentils",
+			"potage":                "soup",
+			"soupe":                 "soup",
+			"veloute":               "cream soup",
+			// Proteins.
+			"poulet":    "chicken",
+			"dinde":     "turkey",
+			"canard":    "duck",
+			"boeuf":     "beef",
+			"veau":      "veal",
+			"porc":      "pork",
+			"agneau":    "lamb",
+			"jambon":    "ham",
+			"lardons":   "bacon bits",
+			"saucisse":  "sausage",
+			"merguez":   "spicy lamb sausage",
+			"poisson":   "fish",
+			"colin":     "pollock",
+			"cabillaud": "cod",
+			"merlu":     "hake",
+			"saumon":    "salmon",
+			"thon":      "tuna",
+			"moules":    "mussels",
+			"crevettes": "prawns",
+			"oeuf":      "egg",
+			"oeufs":     "eggs",
+			"fromage":   "cheese",
+			"filet":     "fillet",
+			"cuisse":    "leg",
+			"escalope":  "escalope",
+			"emince":    "strips",
+			"boulettes": "meatballs",
+			// Cooking and sauces.
+			"beurre blanc": "white butter sauce",
+			"a la creme":   "in cream sauce",
+			"au four":      "baked",
+			"au curry":     "curry",
+			"roti":         "roast",
+			"rotie":        "roast",
+			"grille":       "grilled",
+			"grillee":      "grilled",
+			"pane":         "breaded",
+			"panee":        "breaded",
+			"frit":         "fried",
+			"vapeur":       "steamed",
+			"saute":        "sautéed",
+			"mijote":       "stewed",
+			"braise":       "braised",
+			"gratine":      "au gratin",
+			"gratin":       "gratin",
+			"sauce":        "sauce",
+			"creme":        "cream",
+			"moutarde":     "mustard",
+			"poivre":       "pepper",
+			"ail":          "garlic",
+			"fines herbes": "herbs",
+			// Desserts.
+			"tarte":    "tart",
+			"gateau":   "cake",
+			"yaourt":   "yoghurt",
+			"compote":  "stewed fruit",
+			"fruit":    "fruit",
+			"fruits":   "fruit",
+			"pomme":    "apple",
+			"pommes":   "apples",
+			"poire":    "pear",
+			"chocolat": "chocolate",
+			// Connectors.
+			"et":     "and",
+			"avec":   "with",
+			"sans":   "without",
+			"de":     "of",
+			"du":     "of",
+			"aux":    "with",
+			"au":     "with",
+			"a":      "with",
+			"a la":   "with",
+			"maison": "homemade",
+		},
+	}
+)
+
+type Translator struct {
+	Language string
+	phrases  map[string]string
+	longest  int
+}
+
+func NewTranslator(language string) *Translator {
+	t := new(Translator)
+	t.Language = language
+	t.phrases = make(map[string]string)
+
+	for k, v := range glossaries[language] {
+		t.AddPhrase(k, v)
+	}
+
+	return t
+}
+
+func (t *Translator) AddPhrase(phrase, translation string) {
+	key := glossaryKey(phrase)
+	if key == "" {
+		return
+	}
+
+	t.phrases[key] = translation
+	if n := len(wordRegex.FindAllString(key, -1)); n > t.longest {
+		t.longest = n
+	}
+}
+
+func (t *Translator) Len() int {
+	return len(t.phrases)
+}
+
+func (t *Translator) LoadGlossaryFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var languages map[string]map[string]string
+	err = json.NewDecoder(file).Decode(&languages)
+	if err != nil {
+		return ErrInvalidGlossary
+	}
+
+	for k, v := range languages[t.Language] {
+		t.AddPhrase(k, v)
+	}
+
+	return nil
+}
+
+func (t *Translator) Translate(name string) string {
+	words := wordRegex.FindAllStringIndex(name, -1)
+
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(words); {
+		matched := 0
+		for n := t.longest; n > 0; n-- {
+			if i+n > len(words) {
+				continue
+			}
+
+			key, ok := phraseKey(name, words[i:i+n])
+			if !ok {
+				continue
+			}
+
+			translation, pres := t.phrases[key]
+			if !pres {
+				continue
+			}
+
+			start, end := words[i][0], words[i+n-1][1]
+			b.WriteString(name[last:start])
+			b.WriteString(matchCase(name[start:end], translation))
+			last = end
+			matched = n
+			break
+		}
+
+		if matched == 0 {
+			start, end := words[i][0], words[i][1]
+			apostrophe := elisionApostrophe(name[end:])
+			if replacement, pres := elisions[strings.ToLower(name[start:end])]; pres && apostrophe > 0 {
+				b.WriteString(name[last:start])
+				b.WriteString(matchCase(name[start:end], replacement))
+				last = end + apostrophe
+			}
+			matched = 1
+		}
+		i += matched
+	}
+
+	b.WriteString(name[last:])
+	return b.String()
+}
+
+func (t *Translator) TranslateDays(days []*Day) {
+	for _, d := range days {
+		for _, meals := range d.Meals {
+			for _, m := range meals {
+				if m.Translations == nil {
+					m.Translations = make(map[string]string)
+				}
+				m.Translations[t.Language] = t.Translate(m.Name)
+			}
+		}
+	}
+}
+
+func phraseKey(s string, words [][]int) (string, bool) {
+	var b strings.Builder
+	for i, w := range words {
+		if i > 0 {
+			sep := s[words[i-1][1]:w[0]]
+			switch {
+			case strings.TrimSpace(sep) == "":
+				b.WriteByte(' ')
+			case sep == "'" || sep == "’":
+				b.WriteByte('\'')
+			case sep == "-":
+				b.WriteByte('-')
+			default:
+				return "", false
+			}
+		}
+		b.WriteString(s[w[0]:w[1]])
+	}
+
+	return strings.ToLower(foldAccents(b.String())), true
+}
+
+func elisionApostrophe(s string) int {
+	switch {
+	case strings.HasPrefix(s, "'"):
+		return 1
+	case strings.HasPrefix(s, "’"):
+		return len("’")
+	default:
+		return 0
+	}
+}
+
+func glossaryKey(phrase string) string {
+	phrase = strings.ReplaceAll(phrase, "’", "'")
+	return strings.ToLower(foldAccents(strings.Join(strings.Fields(phrase), " ")))
+}
+
+func matchCase(original, translation string) string {
+	r, _ := utf8.DecodeRuneInString(original)
+	if !unicode.IsUpper(r) {
+		return translation
+	}
+
+	return capitalize(translation)
+}
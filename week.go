@@ -14,6 +14,10 @@ import (
 	"github.com/extrame/xls"
 )
 
+const (
+	weekIdFormat = "2006-01-02"
+)
+
 var (
 	ErrInvalidLinkText  = errors.New("invalid link text content")
 	ErrNoLink           = errors.New("invalid menu link")
@@ -113,6 +117,14 @@ type Week struct {
 	LinkOrPath string    `json:"path"`
 }
 
+func (w *Week) ID() string {
+	return w.Start.Format(weekIdFormat)
+}
+
+func (w *Week) title() string {
+	return "Menu du " + formatDate(w.Start) + " au " + formatDate(w.End)
+}
+
 func (w *Week) FetchDays() error {
 	days, err := w.daysFetcher()
 	if err != nil {
@@ -160,6 +172,42 @@ func (w *Week) Nearest() (*Day, error) {
 	return nil, nil
 }
 
+func (w *Week) Day(t time.Time) (*Day, error) {
+	days, err := w.GetDays()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range days {
+		if !t.Before(d.Start) && !t.After(d.End) {
+			return d, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (w *Week) Filter(keep func(*Meal) bool) (*Week, error) {
+	days, err := w.GetDays()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := new(Week)
+	filtered.Start = w.Start
+	filtered.End = w.End
+	filtered.LinkOrPath = w.LinkOrPath
+	filtered.Days = make([]*Day, 0, len(days))
+	for _, d := range days {
+		filtered.Days = append(filtered.Days, d.Filter(keep))
+	}
+	filtered.daysFetcher = func() ([]*Day, error) {
+		return filtered.Days, nil
+	}
+
+	return filtered, nil
+}
+
 func daysFromReader(rc io.ReadSeeker, start time.Time) ([]*Day, error) {
 	book, err := xls.OpenReader(rc, "utf-8")
 	if err != nil {